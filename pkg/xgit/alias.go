@@ -0,0 +1,304 @@
+package xgit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 通过 bm tj 添加的别名默认所在的分类
+const customCategory = "自定义命令"
+
+// 新建用户配置文件时的内容
+const emptyUserConfig = "{\n  \"commands\": {}\n}\n"
+
+// 用户配置文件的路径
+func userConfigPath() (string, error) {
+	dir := userConfigDir()
+	if dir == "" {
+		return "", errors.New("无法确定用户配置目录")
+	}
+	return filepath.Join(dir, "commands.json"), nil
+}
+
+// 读取用户配置文件，不存在时返回空配置
+func readUserConfig() (path string, data []byte, cfg *CommandConfig, err error) {
+	path, err = userConfigPath()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	data, err = os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		data = []byte(emptyUserConfig)
+	} else if err != nil {
+		return "", nil, nil, err
+	}
+	cfg, err = parseConfig(data, path)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("%v\n运行 'xgit pz jc' 检查配置文件", err)
+	}
+	return path, data, cfg, nil
+}
+
+// 写入用户配置文件：先写临时文件再替换，避免写到一半时损坏原文件
+func writeUserConfig(path string, data []byte) error {
+	if _, err := parseConfig(data, path); err != nil {
+		return fmt.Errorf("修改后的配置无法解析，未写入: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".commands-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil {
+		if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+			return err
+		}
+	} else if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// 别名管理命令
+func (e *Engine) aliasCommand(args []string) error {
+	if len(args) == 0 {
+		e.showAliasUsage()
+		return nil
+	}
+
+	var err error
+	switch args[0] {
+	case "tj":
+		err = e.addAlias(args[1:])
+	case "sc":
+		err = e.removeAlias(args[1:])
+	case "lb":
+		err = e.listAliases()
+	case "dr":
+		err = e.importGitAliases(args[1:])
+	case "dc":
+		err = e.exportAliases(args[1:])
+	case "sc-py":
+		err = e.generatePinyin(args[1:])
+	default:
+		fmt.Fprintf(e.Stdout, "未知的别名命令: %s\n", args[0])
+		e.showAliasUsage()
+		return ErrUnknownCommand
+	}
+	if err != nil {
+		fmt.Fprintf(e.Stdout, "错误: %v\n", err)
+	}
+	return err
+}
+
+func (e *Engine) showAliasUsage() {
+	fmt.Fprintln(e.Stdout, "用法:")
+	fmt.Fprintln(e.Stdout, "  xgit bm tj <别名> [-d 说明] [-c 分类] [-f] -- <git参数...>  # 添加别名 (tian jia)")
+	fmt.Fprintln(e.Stdout, "  xgit bm sc <别名>                                          # 删除别名 (shan chu)")
+	fmt.Fprintln(e.Stdout, "  xgit bm lb                                                 # 列出自定义别名 (lie biao)")
+	fmt.Fprintln(e.Stdout, "  xgit bm dr [-f] [别名...]                                  # 导入git配置中的别名 (dao ru)")
+	fmt.Fprintln(e.Stdout, "  xgit bm dc [--remove] [文件]                               # 导出为git别名 (dao chu)")
+	fmt.Fprintln(e.Stdout, "  xgit bm sc-py <中文名称>                                   # 生成拼音别名 (sheng cheng pin yin)")
+	fmt.Fprintln(e.Stdout)
+	fmt.Fprintln(e.Stdout, "别名保存在用户配置中，-f 覆盖已有的同名别名")
+}
+
+// 检查别名能否使用，force 时允许覆盖已有的别名
+func (e *Engine) checkAliasName(name string, force bool) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsFunc(name, func(r rune) bool { return r <= ' ' }) {
+		return fmt.Errorf("无效的别名 %q：不能为空、以 - 开头或包含空白", name)
+	}
+	if isBuiltinCommand(name) {
+		return fmt.Errorf("%s 是 xgit 自身的命令，不能作为别名", name)
+	}
+	if e.isGitCommand(name) {
+		return fmt.Errorf("%s 是原生git命令，作为别名会遮蔽 xgit %s", name, name)
+	}
+	if force {
+		return nil
+	}
+	// 只来自git配置的别名可以被xgit的别名遮蔽
+	if res, exists := e.Resolve(name); exists && !e.isGitAlias(res.Name) {
+		return fmt.Errorf("别名 %s 已存在: %s\n使用 -f 覆盖", name, e.commandHelp[res.Name])
+	}
+	return nil
+}
+
+// 添加别名：xgit bm tj <别名> [-d 说明] [-c 分类] [-f] -- <git参数...>
+func (e *Engine) addAlias(args []string) error {
+	var name, description, category string
+	force := false
+	i := 0
+	for ; i < len(args) && args[i] != "--"; i++ {
+		switch arg := args[i]; arg {
+		case "-d", "--description", "-c", "--category":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s 需要提供一个值", arg)
+			}
+			i++
+			if arg == "-d" || arg == "--description" {
+				description = args[i]
+			} else {
+				category = args[i]
+			}
+		case "-f", "--force":
+			force = true
+		default:
+			if strings.HasPrefix(arg, "-") || name != "" {
+				return fmt.Errorf("无法识别的参数: %s\n用法: xgit bm tj <别名> [-d 说明] [-c 分类] [-f] -- <git参数...>", arg)
+			}
+			name = arg
+		}
+	}
+
+	var gitArgs []string
+	if i < len(args) {
+		gitArgs = args[i+1:]
+	}
+	// 允许写成 -- git log --oneline
+	if len(gitArgs) > 0 && gitArgs[0] == "git" {
+		gitArgs = gitArgs[1:]
+	}
+	if name == "" || len(gitArgs) == 0 {
+		return errors.New("需要提供别名和git参数\n用法: xgit bm tj <别名> [-d 说明] [-c 分类] [-f] -- <git参数...>")
+	}
+	if err := e.checkAliasName(name, force); err != nil {
+		return err
+	}
+
+	if category == "" {
+		category = customCategory
+	}
+	if description == "" {
+		description = "自定义别名 → " + formatGitCommand(gitArgs)
+	}
+	cmd := Command{Args: gitArgs, Description: description, Category: category}
+
+	path, data, cfg, err := readUserConfig()
+	if err != nil {
+		return err
+	}
+	if data, err = putUserAlias(data, cfg, name, cmd); err != nil {
+		return fmt.Errorf("无法修改 %s: %v", path, err)
+	}
+
+	if e.DryRun {
+		fmt.Fprintf(e.Stdout, "将在 %s 中添加别名 %s → %s\n", path, name, formatGitCommand(gitArgs))
+		return nil
+	}
+	if err := writeUserConfig(path, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.Stdout, "已添加别名 %s → %s\n", name, formatGitCommand(gitArgs))
+	fmt.Fprintf(e.Stdout, "配置文件: %s\n", path)
+	return nil
+}
+
+// 在用户配置的内容中写入别名，并同步更新 cfg。
+// 用户配置中的同名复合命令会被新的别名替代
+func putUserAlias(data []byte, cfg *CommandConfig, name string, cmd Command) ([]byte, error) {
+	var err error
+	if _, exists := cfg.CompositeCommands[name]; exists {
+		if data, _, err = deleteJSONMember(data, "/composite_commands", name); err != nil {
+			return nil, err
+		}
+		delete(cfg.CompositeCommands, name)
+	}
+	if cfg.Commands == nil {
+		data, err = setJSONMember(data, "", "commands", map[string]Command{name: cmd})
+		cfg.Commands = make(map[string]Command)
+	} else {
+		data, err = setJSONMember(data, "/commands", name, cmd)
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg.Commands[name] = cmd
+	return data, nil
+}
+
+// 删除用户配置中的别名：xgit bm sc <别名>
+func (e *Engine) removeAlias(args []string) error {
+	if len(args) != 1 {
+		return errors.New("用法: xgit bm sc <别名>")
+	}
+	name := args[0]
+
+	path, data, cfg, err := readUserConfig()
+	if err != nil {
+		return err
+	}
+
+	parent := "/commands"
+	if _, exists := cfg.CompositeCommands[name]; exists {
+		parent = "/composite_commands"
+	} else if _, exists := cfg.Commands[name]; !exists {
+		if _, defined := e.Resolve(name); defined {
+			return fmt.Errorf("别名 %s 不是在用户配置中定义的，无法删除", name)
+		}
+		return fmt.Errorf("别名 %s 不存在", name)
+	}
+
+	data, _, err = deleteJSONMember(data, parent, name)
+	if err != nil {
+		return fmt.Errorf("无法修改 %s: %v", path, err)
+	}
+	if e.DryRun {
+		fmt.Fprintf(e.Stdout, "将从 %s 中删除别名 %s\n", path, name)
+		return nil
+	}
+	if err := writeUserConfig(path, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.Stdout, "已删除别名 %s\n", name)
+	return nil
+}
+
+// 列出用户配置中的别名
+func (e *Engine) listAliases() error {
+	path, _, cfg, err := readUserConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Commands) == 0 && len(cfg.CompositeCommands) == 0 {
+		fmt.Fprintln(e.Stdout, "用户配置中还没有别名")
+		fmt.Fprintln(e.Stdout, "使用 'xgit bm tj <别名> -- <git参数...>' 添加")
+		return nil
+	}
+
+	fmt.Fprintf(e.Stdout, "用户配置: %s\n", path)
+	fmt.Fprintln(e.Stdout)
+	for _, name := range sortedKeys(cfg.Commands) {
+		cmd := cfg.Commands[name]
+		fmt.Fprintf(e.Stdout, "  %-6s %s\n", name, formatGitCommand(cmd.Args))
+		if cmd.Description != "" {
+			fmt.Fprintf(e.Stdout, "         %s\n", cmd.Description)
+		}
+	}
+	for _, name := range sortedKeys(cfg.CompositeCommands) {
+		cmd := cfg.CompositeCommands[name]
+		steps := make([]string, 0, len(cmd.Steps))
+		for _, step := range cmd.Steps {
+			steps = append(steps, formatGitCommand(step))
+		}
+		fmt.Fprintf(e.Stdout, "  %-6s %s\n", name, strings.Join(steps, " && "))
+		if cmd.Description != "" {
+			fmt.Fprintf(e.Stdout, "         %s\n", cmd.Description)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package xgit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 使用临时的用户配置目录，返回用户配置文件的路径
+func isolateUserConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	return filepath.Join(dir, "xgit", "commands.json")
+}
+
+// 执行别名管理命令
+func runAlias(e *Engine, args ...string) (string, error) {
+	var err error
+	output := captureOutput(e, func() { err = e.aliasCommand(args) })
+	return output, err
+}
+
+func TestAddAlias_NewFile(t *testing.T) {
+	e := newTestEngine(t)
+	path := isolateUserConfig(t)
+
+	output, err := runAlias(e, "tj", "wdrz", "-d", "我的日志", "--", "git", "log", "--oneline", "-5")
+	if err != nil {
+		t.Fatalf("添加别名失败: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "已添加别名 wdrz → git log --oneline -5") {
+		t.Errorf("输出不正确:\n%s", output)
+	}
+
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Command{Args: []string{"log", "--oneline", "-5"}, Description: "我的日志", Category: customCategory}
+	if !reflect.DeepEqual(cfg.Commands["wdrz"], want) {
+		t.Errorf("写入的别名不正确: %+v", cfg.Commands["wdrz"])
+	}
+}
+
+func TestAddAlias_PreservesExistingConfig(t *testing.T) {
+	e := newTestEngine(t)
+	path := isolateUserConfig(t)
+	original := "{\n    \"settings\": {\"autocorrect\": 10},\n    \"commands\": {\n        \"wip\": {\"args\": [\"commit\", \"-m\", \"wip\"], \"category\": \"常用\"}\n    }\n}\n"
+	writeConfigFile(t, path, original)
+
+	if output, err := runAlias(e, "tj", "tb", "--", "fetch", "--all"); err != nil {
+		t.Fatalf("添加别名失败: %v\n%s", err, output)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "{\n    \"settings\": {\"autocorrect\": 10},\n    \"commands\": {\n        \"wip\": {\"args\": [\"commit\", \"-m\", \"wip\"], \"category\": \"常用\"},\n        \"tb\": {\n") {
+		t.Errorf("应该保留原有内容和格式，得到:\n%s", data)
+	}
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *cfg.Settings.Autocorrect != 10 || len(cfg.Commands) != 2 {
+		t.Errorf("已有的配置不应该丢失: %+v", cfg)
+	}
+
+	if output, err := runAlias(e, "sc", "tb"); err != nil {
+		t.Fatalf("删除别名失败: %v\n%s", err, output)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("添加再删除后应该恢复原样，得到:\n%s", data)
+	}
+}
+
+func TestAddAlias_Collisions(t *testing.T) {
+	e := newTestEngine(t)
+	path := isolateUserConfig(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"内置命令", []string{"tj", "bz", "--", "status"}, "bz 是 xgit 自身的命令"},
+		{"git命令", []string{"tj", "git", "--", "status"}, "git 是 xgit 自身的命令"},
+		{"原生git命令", []string{"tj", "status", "--", "status", "-s"}, "status 是原生git命令"},
+		{"已有别名", []string{"tj", "ts", "--", "push", "--tags"}, "别名 ts 已存在"},
+		{"无效的别名", []string{"tj", "-x", "--", "status"}, "无法识别的参数: -x"},
+		{"缺少git参数", []string{"tj", "ck"}, "需要提供别名和git参数"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := runAlias(e, tt.args...)
+			if err == nil || !strings.Contains(output, tt.want) {
+				t.Errorf("应该报告 %q，得到 %v\n%s", tt.want, err, output)
+			}
+		})
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("失败时不应该写入用户配置")
+	}
+}
+
+func TestAddAlias_Force(t *testing.T) {
+	e := newTestEngine(t)
+	path := isolateUserConfig(t)
+
+	if output, err := runAlias(e, "tj", "-f", "ts", "--", "push", "--follow-tags"); err != nil {
+		t.Fatalf("-f 应该允许覆盖已有的别名: %v\n%s", err, output)
+	}
+	cfg, _ := readConfigFile(path)
+	if !reflect.DeepEqual(cfg.Commands["ts"].Args, []string{"push", "--follow-tags"}) {
+		t.Errorf("应该写入新的参数，得到 %v", cfg.Commands["ts"].Args)
+	}
+
+	// 用户配置中的别名也可以再次覆盖
+	if output, err := runAlias(e, "tj", "ts", "-f", "--", "push"); err != nil {
+		t.Fatalf("覆盖失败: %v\n%s", err, output)
+	}
+	cfg, _ = readConfigFile(path)
+	if len(cfg.Commands) != 1 || !reflect.DeepEqual(cfg.Commands["ts"].Args, []string{"push"}) {
+		t.Errorf("应该替换原来的别名，得到 %+v", cfg.Commands)
+	}
+}
+
+func TestAddAlias_DryRun(t *testing.T) {
+	e := newTestEngine(t)
+	path := isolateUserConfig(t)
+	e.DryRun = true
+
+	output, err := runAlias(e, "tj", "ck", "--", "status")
+	if err != nil || !strings.Contains(output, "将在 "+path+" 中添加别名 ck → git status") {
+		t.Errorf("预演输出不正确: %v\n%s", err, output)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("预演时不应该写入用户配置")
+	}
+}
+
+func TestRemoveAlias(t *testing.T) {
+	e := newTestEngine(t)
+	path := isolateUserConfig(t)
+	writeConfigFile(t, path, `{"composite_commands": {"tbts": {"steps": [["fetch"], ["push"]]}}}`)
+
+	if output, err := runAlias(e, "sc", "tbts"); err != nil {
+		t.Fatalf("应该能删除用户配置中的复合命令: %v\n%s", err, output)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"composite_commands": {}}` {
+		t.Errorf("删除后的内容不正确: %s", data)
+	}
+
+	if output, err := runAlias(e, "sc", "ts"); err == nil || !strings.Contains(output, "不是在用户配置中定义的") {
+		t.Errorf("不能删除内置的别名，得到 %v\n%s", err, output)
+	}
+	if output, err := runAlias(e, "sc", "nosuch"); err == nil || !strings.Contains(output, "别名 nosuch 不存在") {
+		t.Errorf("删除不存在的别名应该报错，得到 %v\n%s", err, output)
+	}
+}
+
+func TestListAliases(t *testing.T) {
+	e := newTestEngine(t)
+	path := isolateUserConfig(t)
+
+	output, _ := runAlias(e, "lb")
+	if !strings.Contains(output, "用户配置中还没有别名") {
+		t.Errorf("没有别名时应该给出提示:\n%s", output)
+	}
+
+	writeConfigFile(t, path, `{
+		"commands": {"wdrz": {"args": ["log", "-5"], "description": "我的日志"}},
+		"composite_commands": {"tbts": {"steps": [["fetch"], ["push"]]}}
+	}`)
+	output, err := runAlias(e, "lb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, element := range []string{"用户配置: " + path, "wdrz   git log -5", "我的日志", "tbts   git fetch && git push"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+}
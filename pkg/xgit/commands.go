@@ -0,0 +1,195 @@
+package xgit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JSON配置结构体
+type Command struct {
+	Args        []string  `json:"args"`
+	Description string    `json:"description"`
+	Names       []string  `json:"names,omitempty"`
+	Category    string    `json:"category"`
+	Order       int       `json:"order,omitempty"`
+	Examples    []Example `json:"examples,omitempty"`
+}
+
+type CompositeCommand struct {
+	Steps       [][]string `json:"steps"`
+	Params      []string   `json:"params,omitempty"`
+	Usage       string     `json:"usage,omitempty"`
+	Handler     string     `json:"handler,omitempty"`
+	Description string     `json:"description"`
+	Names       []string   `json:"names,omitempty"`
+	Category    string     `json:"category"`
+	Order       int        `json:"order,omitempty"`
+	Examples    []Example  `json:"examples,omitempty"`
+}
+
+// 命令的用法示例，Command 为完整的命令行，如 "xgit kl <url>"
+type Example struct {
+	Command     string `json:"command"`
+	Description string `json:"description,omitempty"`
+}
+
+// 命令分类，按 Order 从小到大显示
+type Category struct {
+	Name  string `json:"name"`
+	Order int    `json:"order"`
+}
+
+// 行为设置
+type Settings struct {
+	// 未知命令的自动纠正，与 git 的 help.autocorrect 相同：
+	// 0 或不设置时只给出建议，负数立即执行，正数为执行前等待的时间（单位 0.1 秒）
+	Autocorrect *int `json:"autocorrect,omitempty"`
+	// 执行 reset --hard、push -f 等破坏性操作前是否需要确认，默认需要
+	ConfirmDestructive *bool `json:"confirm_destructive,omitempty"`
+}
+
+type CommandConfig struct {
+	Schema            string                      `json:"$schema,omitempty"`
+	Settings          Settings                    `json:"settings"`
+	Categories        []Category                  `json:"categories"`
+	Commands          map[string]Command          `json:"commands"`
+	CompositeCommands map[string]CompositeCommand `json:"composite_commands"`
+	GitCommands       []string                    `json:"git_commands"`
+}
+
+// 根据配置生成映射
+func (e *Engine) generateMappings() {
+	// 初始化映射
+	e.commandMap = make(map[string][]string)
+	e.compositeCommands = make(map[string][][]string)
+	e.commandHelp = make(map[string]string)
+	e.commandExamples = make(map[string][]Example)
+	e.commandCategories = make(map[string][]string)
+
+	// 处理基本命令
+	for key, cmd := range e.config.Commands {
+		e.commandMap[key] = cmd.Args
+		e.commandHelp[key] = cmd.Description
+		e.commandExamples[key] = cmd.Examples
+
+		// 添加到分类
+		if e.commandCategories[cmd.Category] == nil {
+			e.commandCategories[cmd.Category] = []string{}
+		}
+		e.commandCategories[cmd.Category] = append(e.commandCategories[cmd.Category], key)
+	}
+
+	// 处理复合命令
+	for key, cmd := range e.config.CompositeCommands {
+		e.compositeCommands[key] = cmd.Steps
+		e.commandHelp[key] = cmd.Description
+		e.commandExamples[key] = cmd.Examples
+
+		// 添加到分类
+		if e.commandCategories[cmd.Category] == nil {
+			e.commandCategories[cmd.Category] = []string{}
+		}
+		e.commandCategories[cmd.Category] = append(e.commandCategories[cmd.Category], key)
+	}
+
+	// 设置Git命令列表
+	e.gitCommands = e.config.GitCommands
+	e.gitCommandIndex = newNameIndex(e.gitCommands)
+	e.commandIndex = newNameIndex(sortedKeys(e.commandMap), sortedKeys(e.compositeCommands), e.gitCommands)
+
+	// 处理命令的其他名称
+	e.registerNames()
+
+	e.sortCategories()
+}
+
+// 对分类及分类内的命令排序，保证帮助、映射表和补全的输出稳定：
+// 分类按配置的 order 排列，未声明的分类按名称排在最后；
+// 分类内的命令按 order 排列，order 相同时按命令名排列
+func (e *Engine) sortCategories() {
+	weights := make(map[string]int)
+	for _, category := range e.config.Categories {
+		weights[category.Name] = category.Order
+	}
+
+	e.categoryOrder = make([]string, 0, len(e.commandCategories))
+	for category, commands := range e.commandCategories {
+		e.categoryOrder = append(e.categoryOrder, category)
+		sort.Slice(commands, func(i, j int) bool {
+			oi, oj := e.commandOrder(commands[i]), e.commandOrder(commands[j])
+			if oi != oj {
+				return oi < oj
+			}
+			return commands[i] < commands[j]
+		})
+	}
+
+	sort.Slice(e.categoryOrder, func(i, j int) bool {
+		wi, declaredI := weights[e.categoryOrder[i]]
+		wj, declaredJ := weights[e.categoryOrder[j]]
+		if declaredI != declaredJ {
+			return declaredI
+		}
+		if wi != wj {
+			return wi < wj
+		}
+		return e.categoryOrder[i] < e.categoryOrder[j]
+	})
+}
+
+// 命令的排序权重
+func (e *Engine) commandOrder(name string) int {
+	if cmd, exists := e.config.Commands[name]; exists {
+		return cmd.Order
+	}
+	return e.config.CompositeCommands[name].Order
+}
+
+// xgit 自身处理的命令，不能被配置中的别名使用
+var builtinCommands = []string{"bz", "help", "git", "completion", "pz", "bm"}
+
+// 检查是否是 xgit 自身处理的命令
+func isBuiltinCommand(command string) bool {
+	for _, builtin := range builtinCommands {
+		if command == builtin {
+			return true
+		}
+	}
+	return false
+}
+
+// 检查单个命令的用法示例是否引用了不存在的命令，返回发现的问题。
+// xgit pz jc 对每个命令调用它
+func (e *Engine) checkCommandExamples(name string) []string {
+	var problems []string
+	for _, example := range e.commandExamples[name] {
+		fields := strings.Fields(example.Command)
+		if len(fields) == 0 || fields[0] != "xgit" {
+			problems = append(problems, fmt.Sprintf("命令 %s 的示例 %q 应该以 xgit 开头", name, example.Command))
+			continue
+		}
+
+		// 跳过全局选项
+		fields = fields[1:]
+		for len(fields) > 0 && strings.HasPrefix(fields[0], "-") {
+			fields = fields[1:]
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		target := fields[0]
+		_, basicExists := e.commandMap[target]
+		_, compositeExists := e.compositeCommands[target]
+		if !basicExists && !compositeExists && !e.isGitCommand(target) && !isBuiltinCommand(target) {
+			problems = append(problems, fmt.Sprintf("命令 %s 的示例 %q 引用了未知命令 %s", name, example.Command, target))
+		}
+	}
+	return problems
+}
+
+// 检查是否是标准git命令
+func (e *Engine) isGitCommand(command string) bool {
+	return e.gitCommandIndex.contains(command)
+}
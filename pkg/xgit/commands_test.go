@@ -1,10 +1,13 @@
-package main
+package xgit
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
 func TestCommandMap(t *testing.T) {
+	e := newTestEngine(t)
 	tests := []struct {
 		name     string
 		command  string
@@ -27,11 +30,13 @@ func TestCommandMap(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, exists := commandMap[tt.command]
+			// 通过 Resolve 查找，命令的其他名称也能找到
+			resolved, exists := e.Resolve(tt.command)
 			if !exists {
 				t.Errorf("命令 %s 不存在于 commandMap 中", tt.command)
 				return
 			}
+			result := resolved.Args
 
 			if len(result) != len(tt.expected) {
 				t.Errorf("命令 %s 的参数数量不匹配，期望 %d，得到 %d", tt.command, len(tt.expected), len(result))
@@ -48,6 +53,7 @@ func TestCommandMap(t *testing.T) {
 }
 
 func TestCompositeCommands(t *testing.T) {
+	e := newTestEngine(t)
 	tests := []struct {
 		name     string
 		command  string
@@ -58,15 +64,23 @@ func TestCompositeCommands(t *testing.T) {
 			"kstj",
 			[][]string{
 				{"add", "."},
-				{"commit", "-m"},
+				{"commit", "-m", "{1}"},
 				{"push"},
 			},
 		},
+		{
+			"远程设置命令",
+			"ycsh",
+			[][]string{
+				{"remote", "add", "origin", "{1}"},
+				{"push", "-u", "origin", "{branch}"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, exists := compositeCommands[tt.command]
+			result, exists := e.compositeCommands[tt.command]
 			if !exists {
 				t.Errorf("复合命令 %s 不存在于 compositeCommands 中", tt.command)
 				return
@@ -94,38 +108,40 @@ func TestCompositeCommands(t *testing.T) {
 }
 
 func TestCommandHelp(t *testing.T) {
+	e := newTestEngine(t)
 	// 测试所有命令都有对应的帮助信息
-	for cmd := range commandMap {
-		if _, exists := commandHelp[cmd]; !exists {
+	for cmd := range e.commandMap {
+		if _, exists := e.commandHelp[cmd]; !exists {
 			t.Errorf("命令 %s 缺少帮助信息", cmd)
 		}
 	}
 
-	for cmd := range compositeCommands {
-		if _, exists := commandHelp[cmd]; !exists {
+	for cmd := range e.compositeCommands {
+		if _, exists := e.commandHelp[cmd]; !exists {
 			t.Errorf("复合命令 %s 缺少帮助信息", cmd)
 		}
 	}
 }
 
 func TestCommandCategories(t *testing.T) {
+	e := newTestEngine(t)
 	// 测试命令分类是否包含所有命令
 	allCategorizedCommands := make(map[string]bool)
-	for _, commands := range commandCategories {
+	for _, commands := range e.commandCategories {
 		for _, cmd := range commands {
 			allCategorizedCommands[cmd] = true
 		}
 	}
 
 	// 检查基本命令是否都被分类
-	for cmd := range commandMap {
+	for cmd := range e.commandMap {
 		if !allCategorizedCommands[cmd] {
 			t.Errorf("命令 %s 没有被分类", cmd)
 		}
 	}
 
 	// 检查复合命令是否都被分类
-	for cmd := range compositeCommands {
+	for cmd := range e.compositeCommands {
 		if !allCategorizedCommands[cmd] {
 			t.Errorf("复合命令 %s 没有被分类", cmd)
 		}
@@ -133,6 +149,7 @@ func TestCommandCategories(t *testing.T) {
 }
 
 func TestIsGitCommand(t *testing.T) {
+	e := newTestEngine(t)
 	tests := []struct {
 		name     string
 		command  string
@@ -163,7 +180,7 @@ func TestIsGitCommand(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := isGitCommand(tt.command)
+			result := e.isGitCommand(tt.command)
 			if result != tt.expected {
 				t.Errorf("isGitCommand(%s) = %v，期望 %v", tt.command, result, tt.expected)
 			}
@@ -172,14 +189,15 @@ func TestIsGitCommand(t *testing.T) {
 }
 
 func TestCommandMapConsistency(t *testing.T) {
+	e := newTestEngine(t)
 	// 测试命令映射的一致性
-	for cmd, gitCmd := range commandMap {
+	for cmd, gitCmd := range e.commandMap {
 		if len(gitCmd) == 0 {
 			t.Errorf("命令 %s 映射的git命令为空", cmd)
 		}
 
 		// 基本的git命令应该是有效的
-		if len(gitCmd) > 0 && !isGitCommand(gitCmd[0]) {
+		if len(gitCmd) > 0 && !e.isGitCommand(gitCmd[0]) {
 			// 允许一些特殊情况，比如带有 "--" 的命令
 			if gitCmd[0] != "--" {
 				t.Errorf("命令 %s 映射到无效的git命令: %s", cmd, gitCmd[0])
@@ -187,3 +205,87 @@ func TestCommandMapConsistency(t *testing.T) {
 		}
 	}
 }
+
+func TestCategoryOrder(t *testing.T) {
+	e := newTestEngine(t)
+	expected := []string{"仓库操作", "文件操作", "分支操作", "远程操作", "高级操作", "日志操作", "状态操作", "标签操作", "复合命令"}
+	if !reflect.DeepEqual(e.categoryOrder, expected) {
+		t.Errorf("分类顺序不正确，期望 %v，得到 %v", expected, e.categoryOrder)
+	}
+
+	if branchCommands := e.commandCategories["分支操作"]; !reflect.DeepEqual(branchCommands, []string{"ckfz", "fzxq", "ycfz", "cjfz", "qhfz"}) {
+		t.Errorf("分支操作中的命令顺序不正确，得到 %v", branchCommands)
+	}
+}
+
+func TestSortCategories_Undeclared(t *testing.T) {
+	e := newTestEngine(t)
+	e.config = &CommandConfig{
+		Categories: []Category{{Name: "常用", Order: 20}, {Name: "置顶", Order: 10}},
+		Commands: map[string]Command{
+			"b":  {Args: []string{"status"}, Category: "常用"},
+			"a":  {Args: []string{"status"}, Category: "常用"},
+			"z":  {Args: []string{"status"}, Category: "常用", Order: -1},
+			"x":  {Args: []string{"status"}, Category: "置顶"},
+			"y1": {Args: []string{"status"}, Category: "乙"},
+			"y2": {Args: []string{"status"}, Category: "甲"},
+		},
+	}
+	e.generateMappings()
+
+	if !reflect.DeepEqual(e.categoryOrder, []string{"置顶", "常用", "乙", "甲"}) {
+		t.Errorf("未声明的分类应该按名称排在最后，得到 %v", e.categoryOrder)
+	}
+	if !reflect.DeepEqual(e.commandCategories["常用"], []string{"z", "a", "b"}) {
+		t.Errorf("分类内命令应该按 order 再按名称排列，得到 %v", e.commandCategories["常用"])
+	}
+}
+
+// 按帮助中的顺序检查所有命令的用法示例
+func checkExamples(e *Engine) []string {
+	var problems []string
+	for _, category := range e.categoryOrder {
+		for _, name := range e.commandCategories[category] {
+			problems = append(problems, e.checkCommandExamples(name)...)
+		}
+	}
+	return problems
+}
+
+func TestCheckExamples_DefaultConfig(t *testing.T) {
+	e := newTestEngine(t)
+	for _, problem := range checkExamples(e) {
+		t.Errorf("默认配置的示例有问题: %s", problem)
+	}
+}
+
+func TestCheckExamples_UnknownAlias(t *testing.T) {
+	e := newTestEngine(t)
+	e.config = &CommandConfig{
+		Commands: map[string]Command{
+			"ckyc": {
+				Args:     []string{"remote", "-v"},
+				Category: "远程操作",
+				Examples: []Example{
+					{Command: "xgit ckyc"},
+					{Command: "xgit -n ckyc"},
+					{Command: "xgit ycck"},
+					{Command: "git remote -v"},
+				},
+			},
+		},
+		GitCommands: []string{"remote"},
+	}
+	e.generateMappings()
+
+	problems := checkExamples(e)
+	if len(problems) != 2 {
+		t.Fatalf("应该发现 2 个问题，得到 %v", problems)
+	}
+	if !strings.Contains(problems[0], "未知命令 ycck") {
+		t.Errorf("应该报告引用了未知命令 ycck，得到 %s", problems[0])
+	}
+	if !strings.Contains(problems[1], "应该以 xgit 开头") {
+		t.Errorf("应该报告示例没有以 xgit 开头，得到 %s", problems[1])
+	}
+}
@@ -0,0 +1,299 @@
+package xgit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 补全脚本中的一个命令
+type completionEntry struct {
+	Name        string
+	Description string
+	// 参数交给git补全时对应的git命令行前缀，nil 表示按文件补全
+	Git []string
+}
+
+// xgit 自身命令的补全说明
+var builtinCompletions = []completionEntry{
+	{Name: "bz", Description: "显示帮助 (bang zhu)"},
+	{Name: "help", Description: "显示帮助"},
+	{Name: "git", Description: "执行原生git命令", Git: []string{}},
+	{Name: "completion", Description: "生成shell补全脚本"},
+	{Name: "pz", Description: "配置管理 (pei zhi)"},
+	{Name: "bm", Description: "别名管理 (bie ming)"},
+}
+
+// 按帮助中的顺序列出所有可补全的命令
+func (e *Engine) completionEntries() []completionEntry {
+	var entries []completionEntry
+
+	for _, category := range e.categoryOrder {
+		for _, name := range e.commandCategories[category] {
+			entry := completionEntry{Name: name, Description: e.commandHelp[name]}
+			if args, exists := e.commandMap[name]; exists {
+				entry.Git = args
+			} else {
+				entry.Git = compositeCompletionPrefix(e.compositeCommands[name])
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	for _, gitCmd := range e.gitCommands {
+		entries = append(entries, completionEntry{
+			Name:        gitCmd,
+			Description: "git " + gitCmd,
+			Git:         []string{gitCmd},
+		})
+	}
+
+	return append(entries, builtinCompletions...)
+}
+
+// 复合命令第一个参数所在步骤中 {1} 之前的部分，如 tbfz 的 ["checkout", "{1}"] 得到 ["checkout"]，
+// 这样 xgit tbfz <TAB> 可以像 git checkout 一样补全分支名
+func compositeCompletionPrefix(steps [][]string) []string {
+	for _, step := range steps {
+		for i, arg := range step {
+			if arg != "{1}" && !strings.HasPrefix(arg, "{1:") {
+				continue
+			}
+			prefix := step[:i]
+			for _, p := range prefix {
+				if strings.Contains(p, "{") {
+					return nil
+				}
+			}
+			return prefix
+		}
+	}
+	return nil
+}
+
+// 生成补全脚本。用法和错误写到 Stderr 并返回 ErrInvalidArgs，
+// 以免 source <(xgit completion ...) 把错误信息当作脚本执行
+func (e *Engine) showCompletion(args []string) error {
+	if len(args) != 1 {
+		fmt.Fprintln(e.Stderr, "用法: xgit completion bash|zsh|fish")
+		return ErrInvalidArgs
+	}
+
+	entries := e.completionEntries()
+	switch args[0] {
+	case "bash":
+		fmt.Fprint(e.Stdout, bashCompletion(entries))
+	case "zsh":
+		fmt.Fprint(e.Stdout, zshCompletion(entries))
+	case "fish":
+		fmt.Fprint(e.Stdout, fishCompletion(entries))
+	default:
+		fmt.Fprintf(e.Stderr, "错误: 不支持的shell: %s（可选: bash, zsh, fish）\n", args[0])
+		return ErrInvalidArgs
+	}
+	return nil
+}
+
+// 按shell规则引用多个参数并以空格连接
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func bashCompletion(entries []completionEntry) string {
+	var names, cases []string
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+		if entry.Git != nil && entry.Name != "git" {
+			cases = append(cases, fmt.Sprintf("        %s) _xgit_git $i %s ;;", shellQuote(entry.Name), shellJoin(entry.Git)))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`# xgit bash 补全脚本，由 xgit completion bash 生成
+# 使用方法: source <(xgit completion bash)
+
+_xgit_commands=` + shellQuote(strings.Join(names, " ")) + `
+
+# 将 "xgit <别名> 参数..." 改写为对应的git命令行，交给git自带的补全处理
+_xgit_git() {
+    local skip=$1
+    shift
+    if ! declare -F __git_main >/dev/null 2>&1; then
+        declare -F _completion_loader >/dev/null 2>&1 && _completion_loader git
+    fi
+    if ! declare -F __git_main >/dev/null 2>&1; then
+        COMPREPLY=($(compgen -f -- "${COMP_WORDS[COMP_CWORD]}"))
+        return
+    fi
+
+    local -a words=(git "$@" "${COMP_WORDS[@]:skip+1}")
+    COMP_CWORD=$((COMP_CWORD - skip + $#))
+    COMP_WORDS=("${words[@]}")
+    COMP_LINE="${words[*]}"
+    COMP_POINT=${#COMP_LINE}
+    compopt -o nospace 2>/dev/null
+    __git_func_wrap __git_main
+}
+
+_xgit() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local i=1
+    # 跳过全局选项
+    while [[ $i -lt $COMP_CWORD && ${COMP_WORDS[i]} == -* ]]; do
+        ((i++))
+    done
+
+    if [[ $i -eq $COMP_CWORD ]]; then
+        COMPREPLY=($(compgen -W "$_xgit_commands -n --dry-run -y --yes" -- "$cur"))
+        return
+    fi
+
+    case "${COMP_WORDS[i]}" in
+        bz|help) COMPREPLY=($(compgen -W "--git --list $_xgit_commands" -- "$cur")) ;;
+        completion) COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur")) ;;
+        git) _xgit_git $i ;;
+`)
+	b.WriteString(strings.Join(cases, "\n"))
+	b.WriteString(`
+        *) COMPREPLY=($(compgen -f -- "$cur")) ;;
+    esac
+}
+
+complete -o bashdefault -o default -F _xgit xgit
+`)
+	return b.String()
+}
+
+func zshCompletion(entries []completionEntry) string {
+	var described, cases []string
+	for _, entry := range entries {
+		described = append(described, "    "+shellQuote(entry.Name+":"+entry.Description))
+		if entry.Git != nil && entry.Name != "git" {
+			cases = append(cases, fmt.Sprintf("    %s) _xgit_git $i %s ;;", shellQuote(entry.Name), shellJoin(entry.Git)))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`#compdef xgit
+# xgit zsh 补全脚本，由 xgit completion zsh 生成
+# 使用方法: source <(xgit completion zsh)，或保存为 fpath 中的 _xgit
+
+# 将 "xgit <别名> 参数..." 改写为对应的git命令行，交给git自带的补全处理
+_xgit_git() {
+  local skip=$1
+  shift
+  words=(git "$@" "${(@)words[skip+1,-1]}")
+  (( CURRENT = CURRENT - skip + 1 + $# ))
+  _normal
+}
+
+_xgit() {
+  local -a commands
+  commands=(
+`)
+	b.WriteString(strings.Join(described, "\n"))
+	b.WriteString(`
+  )
+
+  local i=2
+  # 跳过全局选项
+  while (( i < CURRENT )) && [[ ${words[i]} == -* ]]; do
+    (( i++ ))
+  done
+
+  if (( CURRENT == i )); then
+    _describe -t commands 'xgit 命令' commands
+    compadd -- -n --dry-run -y --yes
+    return
+  fi
+
+  case ${words[i]} in
+    bz|help) compadd -- --git --list; _describe -t commands 'xgit 命令' commands ;;
+    completion) compadd bash zsh fish ;;
+    git) _xgit_git $i ;;
+`)
+	b.WriteString(strings.Join(cases, "\n"))
+	b.WriteString(`
+    *) _files ;;
+  esac
+}
+
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+  _xgit "$@"
+else
+  compdef _xgit xgit
+fi
+`)
+	return b.String()
+}
+
+// fish 单引号字符串中只需转义单引号和反斜杠
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func fishCompletion(entries []completionEntry) string {
+	var b strings.Builder
+	b.WriteString(`# xgit fish 补全脚本，由 xgit completion fish 生成
+# 使用方法: xgit completion fish > ~/.config/fish/completions/xgit.fish
+
+# 除全局选项外还没有输入命令
+function __xgit_needs_command
+    set -l tokens (commandline -opc)
+    for t in $tokens[2..-1]
+        string match -q -- '-*' $t; or return 1
+    end
+    return 0
+end
+
+# 已输入的命令是否为参数中的某一个
+function __xgit_using_command
+    set -l tokens (commandline -opc)
+    for t in $tokens[2..-1]
+        string match -q -- '-*' $t; and continue
+        contains -- $t $argv
+        return
+    end
+    return 1
+end
+
+# 将 "xgit <别名> 参数..." 改写为对应的git命令行，交给git自带的补全处理
+function __xgit_complete_git
+    set -l tokens (commandline -opc)
+    set -e tokens[1]
+    while string match -q -- '-*' $tokens[1]
+        set -e tokens[1]
+    end
+    set -e tokens[1]
+    complete -C (string join ' ' git (string escape -- $argv $tokens) (commandline -ct))
+end
+
+complete -c xgit -f
+complete -c xgit -n __xgit_needs_command -s n -l dry-run -d '预演：只打印将要执行的git命令'
+complete -c xgit -n __xgit_needs_command -s y -l yes -d '跳过危险操作的确认'
+complete -c xgit -n '__xgit_using_command bz help' -l git -d '查看对应的git命令'
+complete -c xgit -n '__xgit_using_command bz help' -l list -d '显示完整映射表'
+complete -c xgit -n '__xgit_using_command completion' -a 'bash zsh fish'
+complete -c xgit -n '__xgit_using_command git' -a '(__xgit_complete_git)'
+`)
+
+	for _, entry := range entries {
+		name := fishQuote(entry.Name)
+		fmt.Fprintf(&b, "complete -c xgit -n __xgit_needs_command -a %s -d %s\n", name, fishQuote(entry.Description))
+		fmt.Fprintf(&b, "complete -c xgit -n '__xgit_using_command bz help' -a %s -d %s\n", name, fishQuote(entry.Description))
+		switch {
+		case entry.Name == "git":
+		case entry.Git != nil:
+			fmt.Fprintf(&b, "complete -c xgit -n %s -a %s\n",
+				fishQuote("__xgit_using_command "+entry.Name),
+				fishQuote("(__xgit_complete_git "+shellJoin(entry.Git)+")"))
+		default:
+			fmt.Fprintf(&b, "complete -c xgit -n %s -F\n", fishQuote("__xgit_using_command "+entry.Name))
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package xgit
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompositeCompletionPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    [][]string
+		expected []string
+	}{
+		{"同步分支", [][]string{{"fetch", "{2:origin}"}, {"checkout", "{1}"}}, []string{"checkout"}},
+		{"快速提交", [][]string{{"add", "."}, {"commit", "-m", "{1}"}}, []string{"commit", "-m"}},
+		{"前缀中有其他占位符", [][]string{{"push", "{2}", "{1}"}}, nil},
+		{"没有参数", [][]string{{"status"}}, nil},
+	}
+
+	for _, tt := range tests {
+		if result := compositeCompletionPrefix(tt.steps); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%s: compositeCompletionPrefix = %v，期望 %v", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestCompletionEntries(t *testing.T) {
+	e := newTestEngine(t)
+	entries := e.completionEntries()
+
+	byName := make(map[string]completionEntry)
+	for _, entry := range entries {
+		byName[entry.Name] = entry
+	}
+
+	if entry := byName["qhfz"]; !reflect.DeepEqual(entry.Git, []string{"checkout"}) || entry.Description != e.commandHelp["qhfz"] {
+		t.Errorf("qhfz 的补全信息不正确: %+v", entry)
+	}
+	if entry := byName["tbfz"]; !reflect.DeepEqual(entry.Git, []string{"checkout"}) {
+		t.Errorf("tbfz 应该按 git checkout 补全分支，得到 %+v", entry)
+	}
+	for _, name := range []string{"bz", "git", "completion", "stash"} {
+		if _, exists := byName[name]; !exists {
+			t.Errorf("补全列表中缺少 %s", name)
+		}
+	}
+
+	// 顺序与帮助一致
+	if entries[0].Name != e.commandCategories[e.categoryOrder[0]][0] {
+		t.Errorf("补全列表应该按帮助中的顺序排列，第一个是 %s", entries[0].Name)
+	}
+}
+
+func TestShowCompletion(t *testing.T) {
+	e := newTestEngine(t)
+	tests := []struct {
+		shell    string
+		expected []string
+	}{
+		{"bash", []string{"complete -o bashdefault -o default -F _xgit xgit", "qhfz) _xgit_git $i checkout ;;", "cjfz) _xgit_git $i checkout -b ;;", "-n --dry-run -y --yes"}},
+		{"zsh", []string{"#compdef xgit", "'qhfz:切换分支 (qie huan fen zhi) → git checkout <branch>'", "ycfz) _xgit_git $i branch -r ;;", "compadd -- -n --dry-run -y --yes"}},
+		{"fish", []string{"complete -c xgit -n __xgit_needs_command -a 'kl' -d '克隆仓库 (ke long) → git clone <url>'", "complete -c xgit -n '__xgit_using_command qhfz' -a '(__xgit_complete_git checkout)'", "complete -c xgit -n '__xgit_using_command kstj' -a '(__xgit_complete_git commit -m)'", "complete -c xgit -n __xgit_needs_command -s y -l yes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			output := captureOutput(e, func() {
+				e.showCompletion([]string{tt.shell})
+			})
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("%s 补全脚本中缺少: %s", tt.shell, expected)
+				}
+			}
+		})
+	}
+
+	// 错误写到标准错误，source <(xgit completion zhs) 不会执行错误信息
+	for _, args := range [][]string{{"completion", "powershell"}, {"completion"}} {
+		var stderr bytes.Buffer
+		e.Stderr = &stderr
+		var code int
+		output := captureOutput(e, func() { code = e.Run(args) })
+		if code != 1 || output != "" {
+			t.Errorf("%v 应该以退出码 1 结束且不输出脚本，得到 %d\n%s", args, code, output)
+		}
+		if want := map[int]string{2: "不支持的shell: powershell", 1: "用法: xgit completion"}[len(args)]; !strings.Contains(stderr.String(), want) {
+			t.Errorf("%v 应该在标准错误中提示 %q，实际:\n%s", args, want, stderr.String())
+		}
+	}
+}
+
+// 在真实的bash中加载补全脚本，验证别名的参数会交给git补全
+func TestBashCompletion_Script(t *testing.T) {
+	e := newTestEngine(t)
+	gitCompletion := "/usr/share/bash-completion/completions/git"
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("没有安装 bash")
+	}
+	if _, err := os.Stat(gitCompletion); err != nil {
+		t.Skip("没有安装git的bash补全脚本")
+	}
+
+	isolateGit(t)
+	repo := t.TempDir()
+	runGitIn(t, repo, "init", "-q", "-b", "main")
+	runGitIn(t, repo, "commit", "-q", "--allow-empty", "-m", "初始提交")
+	runGitIn(t, repo, "branch", "feature-login")
+
+	scriptPath := filepath.Join(t.TempDir(), "xgit.bash")
+	script := captureOutput(e, func() { e.showCompletion([]string{"bash"}) })
+	if err := os.WriteFile(scriptPath, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := func(words ...string) string {
+		driver := `source ` + gitCompletion + `
+source ` + scriptPath + `
+COMP_WORDS=("$@"); COMP_CWORD=$((${#COMP_WORDS[@]}-1)); COMP_LINE="$*"; COMP_POINT=${#COMP_LINE}
+_xgit
+echo "${COMPREPLY[*]}"`
+		cmd := exec.Command("bash", append([]string{"-c", driver, "bash"}, words...)...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("执行补全脚本失败: %v\n%s", err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	if result := complete("xgit", "qhf"); result != "qhfz" {
+		t.Errorf("应该补全别名 qhfz，得到 %q", result)
+	}
+	if result := complete("xgit", "qhfz", "feat"); result != "feature-login" {
+		t.Errorf("qhfz 的参数应该按 git checkout 补全分支，得到 %q", result)
+	}
+	if result := complete("xgit", "-n", "tbfz", "feat"); result != "feature-login" {
+		t.Errorf("跳过全局选项后 tbfz 的参数应该补全分支，得到 %q", result)
+	}
+}
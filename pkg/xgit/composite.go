@@ -0,0 +1,187 @@
+package xgit
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 复合命令步骤中支持的占位符：
+//
+//	{1} {2} ...     第 N 个用户参数（必填）
+//	{N:默认值}       第 N 个用户参数，未提供时使用默认值
+//	{args}          未被位置占位符占用的其余参数，展开为多个参数，必须单独作为一个参数
+//	{branch}        当前分支名
+var placeholderPattern = regexp.MustCompile(`\{(\d+)(?::([^{}]*))?\}|\{branch\}`)
+
+const restPlaceholder = "{args}"
+
+// 查找参数中的占位符，结果的格式与 FindAllStringSubmatchIndex 相同。
+// 紧跟在 @ 之后的 {…} 是git的引用语法（如 HEAD@{1}、stash@{0}），不是占位符
+func findPlaceholders(arg string) [][]int {
+	var placeholders [][]int
+	for _, m := range placeholderPattern.FindAllStringSubmatchIndex(arg, -1) {
+		if m[0] > 0 && arg[m[0]-1] == '@' {
+			continue
+		}
+		placeholders = append(placeholders, m)
+	}
+	return placeholders
+}
+
+// 缺少必填参数
+type missingArgError struct {
+	Index int
+}
+
+func (e *missingArgError) Error() string {
+	return fmt.Sprintf("缺少第 %d 个参数", e.Index)
+}
+
+// 多余的参数
+type extraArgsError struct {
+	Args []string
+}
+
+func (e *extraArgsError) Error() string {
+	return fmt.Sprintf("多余的参数: %s", strings.Join(e.Args, " "))
+}
+
+// 复合命令中某一步git命令失败
+type StepError struct {
+	Command  string   // 复合命令名
+	Step     int      // 失败的步骤，从 1 开始
+	Total    int      // 总步骤数
+	Args     []string // 失败步骤的git参数
+	ExitCode int      // git的退出码
+	Err      error
+}
+
+func newStepError(cmdName string, step, total int, args []string, err error) *StepError {
+	code := 1
+	var exitErr *GitExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode > 0 {
+		code = exitErr.ExitCode
+	}
+	return &StepError{Command: cmdName, Step: step, Total: total, Args: args, ExitCode: code, Err: err}
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("复合命令 %s 的第 %d/%d 步 (%s) 失败，退出码 %d", e.Command, e.Step, e.Total, formatGitCommand(e.Args), e.ExitCode)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// 是否为参数绑定错误（缺少参数或多余参数）
+func isArgError(err error) bool {
+	var missing *missingArgError
+	var extra *extraArgsError
+	return errors.As(err, &missing) || errors.As(err, &extra)
+}
+
+// 统计步骤中使用的最大位置占位符，以及是否使用了 {args}
+func placeholderUsage(steps [][]string) (maxIndex int, usesRest bool) {
+	for _, step := range steps {
+		for _, arg := range step {
+			if arg == restPlaceholder {
+				usesRest = true
+				continue
+			}
+			for _, m := range findPlaceholders(arg) {
+				if m[2] < 0 {
+					continue
+				}
+				if n, _ := strconv.Atoi(arg[m[2]:m[3]]); n > maxIndex {
+					maxIndex = n
+				}
+			}
+		}
+	}
+	return maxIndex, usesRest
+}
+
+// 将用户参数绑定到复合命令的步骤中，branch 仅在步骤使用 {branch} 时调用
+func expandSteps(steps [][]string, args []string, branch func() (string, error)) ([][]string, error) {
+	maxIndex, usesRest := placeholderUsage(steps)
+
+	var rest []string
+	if len(args) > maxIndex {
+		rest = args[maxIndex:]
+	}
+	if len(rest) > 0 && !usesRest {
+		return nil, &extraArgsError{Args: rest}
+	}
+
+	var currentBranch string
+	expanded := make([][]string, 0, len(steps))
+	for _, step := range steps {
+		out := make([]string, 0, len(step))
+		for _, arg := range step {
+			if arg == restPlaceholder {
+				out = append(out, rest...)
+				continue
+			}
+
+			var value strings.Builder
+			last := 0
+			for _, m := range findPlaceholders(arg) {
+				value.WriteString(arg[last:m[0]])
+				last = m[1]
+				if m[2] < 0 {
+					if currentBranch == "" {
+						var err error
+						if currentBranch, err = branch(); err != nil {
+							return nil, err
+						}
+					}
+					value.WriteString(currentBranch)
+					continue
+				}
+				n, _ := strconv.Atoi(arg[m[2]:m[3]])
+				switch {
+				case n >= 1 && n <= len(args):
+					value.WriteString(args[n-1])
+				case m[4] >= 0:
+					value.WriteString(arg[m[4]:m[5]])
+				default:
+					return nil, &missingArgError{Index: n}
+				}
+			}
+			value.WriteString(arg[last:])
+			out = append(out, value.String())
+		}
+		expanded = append(expanded, out)
+	}
+	return expanded, nil
+}
+
+// 复合命令的用法说明，未配置时根据参数名生成
+func compositeUsage(cmdName string, spec CompositeCommand) string {
+	if spec.Usage != "" {
+		return spec.Usage
+	}
+	usage := "xgit " + cmdName
+	for _, param := range spec.Params {
+		usage += " <" + param + ">"
+	}
+	return usage
+}
+
+// 打印参数绑定失败的提示
+func (e *Engine) printCompositeArgError(cmdName string, spec CompositeCommand, err error) {
+	var missing *missingArgError
+	if errors.As(err, &missing) {
+		name := fmt.Sprintf("第 %d 个参数", missing.Index)
+		if missing.Index >= 1 && missing.Index <= len(spec.Params) {
+			name = spec.Params[missing.Index-1]
+		}
+		fmt.Fprintf(e.Stdout, "错误: 需要提供%s\n", name)
+	} else {
+		fmt.Fprintf(e.Stdout, "错误: %v\n", err)
+	}
+	fmt.Fprintf(e.Stdout, "用法: %s\n", compositeUsage(cmdName, spec))
+}
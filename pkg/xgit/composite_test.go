@@ -0,0 +1,140 @@
+package xgit
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandSteps(t *testing.T) {
+	branch := func() (string, error) { return "feature", nil }
+
+	tests := []struct {
+		name     string
+		steps    [][]string
+		args     []string
+		expected [][]string
+	}{
+		{
+			"位置参数",
+			[][]string{{"add", "."}, {"commit", "-m", "{1}"}, {"push"}},
+			[]string{"修复问题"},
+			[][]string{{"add", "."}, {"commit", "-m", "修复问题"}, {"push"}},
+		},
+		{
+			"默认值",
+			[][]string{{"push", "-u", "origin", "{2:main}"}},
+			[]string{"url"},
+			[][]string{{"push", "-u", "origin", "main"}},
+		},
+		{
+			"默认值被参数覆盖",
+			[][]string{{"push", "-u", "origin", "{2:main}"}},
+			[]string{"url", "dev"},
+			[][]string{{"push", "-u", "origin", "dev"}},
+		},
+		{
+			"其余参数展开",
+			[][]string{{"commit", "-m", "{1}"}, {"add", "{args}"}},
+			[]string{"msg", "a.txt", "b.txt"},
+			[][]string{{"commit", "-m", "msg"}, {"add", "a.txt", "b.txt"}},
+		},
+		{
+			"其余参数为空",
+			[][]string{{"log", "{args}"}},
+			nil,
+			[][]string{{"log"}},
+		},
+		{
+			"嵌入参数中的占位符",
+			[][]string{{"push", "origin", "{branch}:refs/heads/{1}"}},
+			[]string{"release"},
+			[][]string{{"push", "origin", "feature:refs/heads/release"}},
+		},
+		{
+			"git的引用语法不是占位符",
+			[][]string{{"stash", "show", "stash@{0}"}, {"reset", "--keep", "HEAD@{1}"}, {"log", "{1}@{upstream}"}},
+			[]string{"main"},
+			[][]string{{"stash", "show", "stash@{0}"}, {"reset", "--keep", "HEAD@{1}"}, {"log", "main@{upstream}"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := expandSteps(tt.steps, tt.args, branch)
+			if err != nil {
+				t.Fatalf("expandSteps 返回错误: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expandSteps 结果不正确，期望 %v，得到 %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestExpandSteps_Errors(t *testing.T) {
+	branch := func() (string, error) { return "", errors.New("分离头指针") }
+
+	if _, err := expandSteps([][]string{{"commit", "-m", "{1}"}}, nil, branch); err == nil {
+		t.Error("缺少必填参数时应该返回错误")
+	} else if e, ok := err.(*missingArgError); !ok || e.Index != 1 {
+		t.Errorf("应该返回 missingArgError{Index: 1}，得到 %v", err)
+	}
+
+	if _, err := expandSteps([][]string{{"commit", "-m", "{1}"}}, []string{"a", "b"}, branch); err == nil {
+		t.Error("存在多余参数时应该返回错误")
+	} else if !strings.Contains(err.Error(), "b") {
+		t.Errorf("错误信息中应该包含多余的参数，得到 %v", err)
+	}
+
+	if _, err := expandSteps([][]string{{"push", "origin", "{branch}"}}, nil, branch); err == nil {
+		t.Error("无法获取当前分支时应该返回错误")
+	}
+}
+
+func TestExecuteCompositeCommand_StashReference(t *testing.T) {
+	e, err := New(&CommandConfig{
+		CompositeCommands: map[string]CompositeCommand{
+			"ckzc": {Steps: [][]string{{"stash", "show", "-p", "stash@{0}"}}, Params: []string{"文件"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := withRecordingRunner(t, e)
+
+	output := captureOutput(e, func() { err = e.Execute("ckzc", nil) })
+	if err != nil {
+		t.Fatalf("执行失败: %v\n%s", err, output)
+	}
+	if commands := runner.Commands(); !reflect.DeepEqual(commands, []string{"git stash show -p stash@{0}"}) {
+		t.Errorf("stash@{0} 应该原样传给git，得到 %v", commands)
+	}
+
+	output = captureOutput(e, func() { err = e.Execute("ckzc", []string{"a.txt"}) })
+	if err == nil || !strings.Contains(output, "错误: 多余的参数: a.txt") {
+		t.Errorf("stash@{0} 不应该消耗用户参数，得到 %v\n%s", err, output)
+	}
+}
+
+func TestPrintCompositeArgError_InvalidIndex(t *testing.T) {
+	e := newTestEngine(t)
+	spec := CompositeCommand{Params: []string{"文件"}}
+	output := captureOutput(e, func() { e.printCompositeArgError("xx", spec, &missingArgError{Index: 0}) })
+	if !strings.Contains(output, "错误: 需要提供第 0 个参数") {
+		t.Errorf("无效的参数编号不应该读取 params，实际输出:\n%s", output)
+	}
+}
+
+func TestCompositeUsage(t *testing.T) {
+	spec := CompositeCommand{Params: []string{"远程", "分支"}}
+	if usage := compositeUsage("tb", spec); usage != "xgit tb <远程> <分支>" {
+		t.Errorf("自动生成的用法不正确，得到 %s", usage)
+	}
+
+	spec.Usage = "xgit tb <分支>"
+	if usage := compositeUsage("tb", spec); usage != spec.Usage {
+		t.Errorf("应该优先使用配置的用法，得到 %s", usage)
+	}
+}
@@ -0,0 +1,192 @@
+package xgit
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// 编译进二进制的默认配置，外部配置文件只在其基础上添加或覆盖
+//
+//go:embed commands.json
+var defaultConfigData []byte
+
+// 系统级配置目录
+var systemConfigDir = "/etc/xgit"
+
+// 仓库级配置文件名（位于仓库根目录）
+const repoConfigName = ".xgit.json"
+
+// 配置层，后加载的层会添加或覆盖前面层中的条目
+type configLayer struct {
+	Name string
+	Path string
+}
+
+// 按优先级从低到高列出所有配置层。仓库配置从 dir 所在的仓库查找，
+// dir 为空时使用当前目录
+func configLayers(dir string) []configLayer {
+	layers := []configLayer{{Name: "系统配置", Path: filepath.Join(systemConfigDir, "commands.json")}}
+
+	if dir := userConfigDir(); dir != "" {
+		layers = append(layers, configLayer{Name: "用户配置", Path: filepath.Join(dir, "commands.json")})
+	}
+
+	if dir, err := filepath.Abs(dir); err == nil {
+		if root := findRepoRoot(dir); root != "" {
+			layers = append(layers, configLayer{Name: "仓库配置", Path: filepath.Join(root, repoConfigName)})
+		}
+	}
+
+	return layers
+}
+
+// 旧的安装方式在执行文件旁边放置 commands.json。默认配置编译进程序之后不再加载它：
+// 旧版本留下的副本会整体覆盖新版本的内置命令
+func legacyConfigPath() string {
+	execPath, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(execPath), "commands.json")
+}
+
+// 用户配置目录：$XDG_CONFIG_HOME/xgit，未设置时使用 ~/.config/xgit
+func userConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "xgit")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "xgit")
+}
+
+// 从 dir 向上查找包含 .git 的目录，找不到时返回空字符串
+func findRepoRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// 读取并解析单个配置文件
+func readConfigFile(path string) (*CommandConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(data, path)
+}
+
+// 解析配置内容，path 仅用于错误信息
+func parseConfig(data []byte, path string) (*CommandConfig, error) {
+	layerConfig := &CommandConfig{}
+	if err := json.Unmarshal(data, layerConfig); err != nil {
+		return nil, fmt.Errorf("无法解析配置文件 %s: %v", path, err)
+	}
+	return layerConfig, nil
+}
+
+// DefaultConfig 返回编译进程序的默认配置
+func DefaultConfig() (*CommandConfig, error) {
+	return parseConfig(defaultConfigData, "内置默认配置")
+}
+
+// LoadConfig 以内置默认配置为基础，依次合并系统、用户和当前目录所在仓库的配置
+func LoadConfig() (*CommandConfig, error) {
+	return LoadConfigIn("")
+}
+
+// LoadConfigIn 与 LoadConfig 相同，但从 dir 所在的仓库加载仓库配置，
+// 用于在其他目录执行命令的引擎（Engine.Dir）
+func LoadConfigIn(dir string) (*CommandConfig, error) {
+	return loadLayeredConfig(configLayers(dir))
+}
+
+// 以内置默认配置为基础，依次合并所有存在的配置层，不存在的层会被跳过
+func loadLayeredConfig(layers []configLayer) (*CommandConfig, error) {
+	merged, err := DefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, layer := range layers {
+		layerConfig, err := readConfigFile(layer.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", layer.Name, err)
+		}
+		mergeConfig(merged, layerConfig)
+	}
+
+	return merged, nil
+}
+
+// 将 src 中的条目合并到 dst，同名条目以 src 为准
+func mergeConfig(dst, src *CommandConfig) {
+	if dst.Commands == nil {
+		dst.Commands = make(map[string]Command)
+	}
+	if dst.CompositeCommands == nil {
+		dst.CompositeCommands = make(map[string]CompositeCommand)
+	}
+
+	for key, cmd := range src.Commands {
+		// 同名的复合命令被基本命令覆盖
+		delete(dst.CompositeCommands, key)
+		dst.Commands[key] = cmd
+	}
+
+	for key, cmd := range src.CompositeCommands {
+		delete(dst.Commands, key)
+		dst.CompositeCommands[key] = cmd
+	}
+
+	if src.Settings.Autocorrect != nil {
+		dst.Settings.Autocorrect = src.Settings.Autocorrect
+	}
+	if src.Settings.ConfirmDestructive != nil {
+		dst.Settings.ConfirmDestructive = src.Settings.ConfirmDestructive
+	}
+
+	for _, category := range src.Categories {
+		replaced := false
+		for i, existing := range dst.Categories {
+			if existing.Name == category.Name {
+				dst.Categories[i] = category
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			dst.Categories = append(dst.Categories, category)
+		}
+	}
+
+	for _, gitCmd := range src.GitCommands {
+		exists := false
+		for _, existing := range dst.GitCommands {
+			if existing == gitCmd {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			dst.GitCommands = append(dst.GitCommands, gitCmd)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package xgit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 写入测试用配置文件
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMergeConfig(t *testing.T) {
+	base := &CommandConfig{
+		Commands: map[string]Command{
+			"ts": {Args: []string{"push"}, Category: "远程操作"},
+			"lq": {Args: []string{"pull"}, Category: "远程操作"},
+		},
+		CompositeCommands: map[string]CompositeCommand{
+			"kstj": {Steps: [][]string{{"add", "."}}},
+		},
+		Categories:  []Category{{Name: "远程操作", Order: 10}},
+		GitCommands: []string{"add", "push"},
+	}
+	override := &CommandConfig{
+		Commands: map[string]Command{
+			"ts":   {Args: []string{"push", "--follow-tags"}, Category: "远程操作"},
+			"kstj": {Args: []string{"commit", "-am"}, Category: "文件操作"},
+		},
+		CompositeCommands: map[string]CompositeCommand{
+			"lq": {Steps: [][]string{{"fetch"}, {"rebase"}}},
+		},
+		Categories:  []Category{{Name: "远程操作", Order: 30}, {Name: "个人命令", Order: 5}},
+		GitCommands: []string{"push", "stash"},
+	}
+
+	mergeConfig(base, override)
+
+	if !reflect.DeepEqual(base.Categories, []Category{{Name: "远程操作", Order: 30}, {Name: "个人命令", Order: 5}}) {
+		t.Errorf("分类应该按名称合并，得到 %v", base.Categories)
+	}
+
+	if args := base.Commands["ts"].Args; !reflect.DeepEqual(args, []string{"push", "--follow-tags"}) {
+		t.Errorf("后加载的层应该覆盖同名命令，得到 %v", args)
+	}
+	if _, exists := base.CompositeCommands["kstj"]; exists {
+		t.Error("被基本命令覆盖的复合命令应该被移除")
+	}
+	if _, exists := base.Commands["lq"]; exists {
+		t.Error("被复合命令覆盖的基本命令应该被移除")
+	}
+	if _, exists := base.CompositeCommands["lq"]; !exists {
+		t.Error("后加载的层应该能添加复合命令")
+	}
+	if !reflect.DeepEqual(base.GitCommands, []string{"add", "push", "stash"}) {
+		t.Errorf("git命令列表应该去重合并，得到 %v", base.GitCommands)
+	}
+}
+
+func TestFindRepoRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := findRepoRoot(sub); got != root {
+		t.Errorf("findRepoRoot(%s) = %s，期望 %s", sub, got, root)
+	}
+
+	outside := t.TempDir()
+	if got := findRepoRoot(outside); got != "" {
+		t.Errorf("仓库外的目录不应找到仓库根目录，得到 %s", got)
+	}
+}
+
+func TestConfigLayers(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(repo, "src")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+
+	layerPaths := func(dir string) []string {
+		var paths []string
+		for _, layer := range configLayers(dir) {
+			paths = append(paths, layer.Path)
+		}
+		return paths
+	}
+
+	// 执行文件旁边的 commands.json 不再作为配置层
+	expected := []string{
+		filepath.Join(systemConfigDir, "commands.json"),
+		filepath.Join(xdg, "xgit", "commands.json"),
+		filepath.Join(repo, repoConfigName),
+	}
+	if paths := layerPaths(""); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("配置层顺序不正确，得到 %v", paths)
+	}
+
+	// 指定目录时从该目录所在的仓库查找仓库配置，而不是当前目录
+	other := t.TempDir()
+	if err := os.Mkdir(filepath.Join(other, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	expected[2] = filepath.Join(other, repoConfigName)
+	if paths := layerPaths(other); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("应该使用指定目录所在仓库的配置，得到 %v", paths)
+	}
+	if paths := layerPaths(t.TempDir()); len(paths) != 2 {
+		t.Errorf("指定目录不在仓库中时不应该有仓库配置，得到 %v", paths)
+	}
+}
+
+func TestLoadConfigIn(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeConfigFile(t, filepath.Join(repo, repoConfigName), `{"commands": {"bs": {"args": ["build"], "category": "仓库命令"}}}`)
+
+	cfg, err := LoadConfigIn(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := cfg.Commands["bs"]; !exists {
+		t.Error("应该加载指定目录所在仓库的配置")
+	}
+
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := cfg.Commands["bs"]; exists {
+		t.Error("LoadConfig 只应该加载当前目录所在仓库的配置")
+	}
+}
+
+func TestLoadLayeredConfig(t *testing.T) {
+	dir := t.TempDir()
+	systemPath := filepath.Join(dir, "system.json")
+	userPath := filepath.Join(dir, "user.json")
+	writeConfigFile(t, systemPath, `{"commands": {"ts": {"args": ["push", "--follow-tags"], "category": "远程操作"}}}`)
+	writeConfigFile(t, userPath, `{"commands": {"wip": {"args": ["commit", "-m", "wip"], "category": "个人命令"}}}`)
+
+	layers := []configLayer{
+		{Name: "安装目录配置", Path: filepath.Join(dir, "missing.json")},
+		{Name: "系统配置", Path: systemPath},
+		{Name: "用户配置", Path: userPath},
+	}
+
+	merged, err := loadLayeredConfig(layers)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if _, exists := merged.Commands["kl"]; !exists {
+		t.Error("应该包含内置默认配置中的命令")
+	}
+	if args := merged.Commands["ts"].Args; !reflect.DeepEqual(args, []string{"push", "--follow-tags"}) {
+		t.Errorf("系统配置应该覆盖内置默认配置，得到 %v", args)
+	}
+	if _, exists := merged.Commands["wip"]; !exists {
+		t.Error("应该包含用户配置中的命令")
+	}
+}
+
+func TestLoadLayeredConfig_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	merged, err := loadLayeredConfig([]configLayer{{Name: "用户配置", Path: filepath.Join(dir, "missing.json")}})
+	if err != nil {
+		t.Fatalf("配置文件不存在时应该使用内置默认配置，得到错误: %v", err)
+	}
+
+	defaults, err := parseConfig(defaultConfigData, "内置默认配置")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(merged, defaults) {
+		t.Error("没有外部配置时应该与内置默认配置一致")
+	}
+}
+
+func TestLoadLayeredConfig_InvalidFile(t *testing.T) {
+	badPath := filepath.Join(t.TempDir(), "bad.json")
+	writeConfigFile(t, badPath, `{"commands": `)
+
+	_, err := loadLayeredConfig([]configLayer{{Name: "仓库配置", Path: badPath}})
+	if err == nil {
+		t.Fatal("配置文件格式错误时应该返回错误")
+	}
+	if !strings.Contains(err.Error(), "仓库配置") || !strings.Contains(err.Error(), badPath) {
+		t.Errorf("错误信息应该包含配置层和文件路径，得到 %v", err)
+	}
+}
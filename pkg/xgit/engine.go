@@ -0,0 +1,192 @@
+// Package xgit 将中文拼音首字母命令解析为git命令并执行。
+//
+// 命令行程序只是 Run 的一层包装；其他程序可以用 New 根据自己的配置创建 Engine，
+// 通过 Resolve 查询别名对应的git命令，或用 Execute 执行命令和复合命令。
+package xgit
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Engine 根据一份命令配置解析并执行拼音命令。
+// 所有输出都写入 Stdout，git命令通过 Runner 执行
+type Engine struct {
+	Runner     GitRunner           // 执行git命令，默认调用系统中的git
+	Dir        string              // git命令的工作目录，为空时使用当前目录
+	Stdin      io.Reader           // 确认提示和交互式git命令的输入
+	Stdout     io.Writer           // xgit 自身以及交互式git命令的输出
+	Stderr     io.Writer           // 交互式git命令的错误输出
+	DryRun     bool                // 预演模式：只打印将要执行的git命令，不实际执行
+	AssumeYes  bool                // 跳过破坏性操作的确认（--yes / -y）
+	Sleep      func(time.Duration) // 自动纠正前的等待
+	GitAliases bool                // Run 时读取git配置中的 [alias]，作为命令使用（命令行程序默认开启）
+
+	config            *CommandConfig
+	commandMap        map[string][]string
+	compositeCommands map[string][][]string
+	commandHelp       map[string]string
+	commandExamples   map[string][]Example
+	commandCategories map[string][]string
+	categoryOrder     []string
+	gitCommands       []string
+	gitCommandIndex   nameIndex // 排序后的原生git命令
+	commandIndex      nameIndex // 所有可执行的命令名，用于按缩写查找
+	gitAliases        map[string]gitAlias
+	alternateNames    map[string]string // 规范化的其他名称 → 命令名
+	stdin             *stdinReader      // 确认时读取 Stdin，Run 复制的引擎共用同一个
+}
+
+// New 根据配置创建引擎，默认使用标准输入输出和系统中的git
+func New(cfg *CommandConfig) (*Engine, error) {
+	for name, cmd := range cfg.CompositeCommands {
+		if cmd.Handler == "" {
+			continue
+		}
+		if _, exists := compositeHandlers[cmd.Handler]; !exists {
+			return nil, fmt.Errorf("复合命令 %s 使用了未知的处理器: %s", name, cmd.Handler)
+		}
+	}
+
+	e := &Engine{
+		Runner: ExecRunner{},
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		Sleep:  time.Sleep,
+		config: cfg,
+		stdin:  &stdinReader{},
+	}
+	e.generateMappings()
+	return e, nil
+}
+
+// Config 返回引擎使用的配置
+func (e *Engine) Config() *CommandConfig {
+	return e.config
+}
+
+// Run 加载分层配置，使用标准输入输出执行一次命令行，返回进程退出码。
+// args 不包含程序名
+func Run(args []string) int {
+	e, err := loadEngine(LoadConfig)
+	if err != nil && isConfigCheck(args) {
+		// 配置本身有错误时仍然可以用 pz jc 找出问题
+		e, err = loadEngine(DefaultConfig)
+	}
+	if err != nil {
+		fmt.Printf("错误：%v\n", err)
+		fmt.Println("运行 'xgit pz jc' 检查配置文件")
+		return 1
+	}
+	e.GitAliases = true
+	return e.Run(args)
+}
+
+func loadEngine(load func() (*CommandConfig, error)) (*Engine, error) {
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+	return New(cfg)
+}
+
+// 命令行是否为配置检查命令
+func isConfigCheck(args []string) bool {
+	args = (&Engine{}).parseGlobalFlags(args)
+	return len(args) >= 2 && args[0] == "pz" && args[1] == "jc"
+}
+
+// Run 解析全局选项并执行命令，返回进程退出码。args 不包含程序名，
+// 全局选项只对本次执行有效
+func (e *Engine) Run(args []string) int {
+	run := *e
+	args = run.parseGlobalFlags(args)
+	if run.GitAliases {
+		run.loadGitAliases()
+	}
+	if err := run.dispatch(args); err != nil {
+		return ExitCode(err)
+	}
+	return 0
+}
+
+// 执行命令行中全局选项之后的部分
+func (e *Engine) dispatch(args []string) error {
+	if len(args) == 0 {
+		e.showUsage()
+		return nil
+	}
+	command := args[0]
+
+	switch command {
+	case "bz", "help":
+		return e.showHelp(args[1:])
+	case "completion":
+		return e.showCompletion(args[1:])
+	case "pz":
+		return e.configCommand(args[1:])
+	case "bm":
+		return e.aliasCommand(args[1:])
+	case "git":
+		// 直接执行git命令
+		return e.journalOperation(args, func() error { return e.executeGitCommand(args[1:]) })
+	default:
+		// 处理拼音命令，撤销命令本身不记录到操作日志
+		if e.isUndoCommand(command) {
+			return e.Execute(command, args[1:])
+		}
+		return e.journalOperation(args, func() error { return e.Execute(command, args[1:]) })
+	}
+}
+
+// 解析命令之前的全局选项，返回剩余参数
+func (e *Engine) parseGlobalFlags(args []string) []string {
+	for len(args) > 0 {
+		switch args[0] {
+		case "--dry-run", "-n":
+			e.DryRun = true
+		case "--yes", "-y":
+			e.AssumeYes = true
+		default:
+			return args
+		}
+		args = args[1:]
+	}
+	return args
+}
+
+// 命令的种类
+type CommandKind int
+
+const (
+	KindBasic     CommandKind = iota + 1 // 映射为一条git命令的拼音命令
+	KindComposite                        // 由多个步骤组成的复合命令
+	KindGit                              // 原样交给git的原生命令
+)
+
+// Resolution 是命令解析的结果
+type Resolution struct {
+	Name      string
+	Kind      CommandKind
+	Args      []string         // 基本命令和原生git命令对应的git参数
+	Composite CompositeCommand // 复合命令的定义
+}
+
+// Resolve 查找命令对应的git命令或复合命令，不执行任何操作。
+// name 可以是命令的其他名称，结果中的 Name 为命令的正式名称
+func (e *Engine) Resolve(name string) (Resolution, bool) {
+	name = e.primaryName(name)
+	if composite, exists := e.config.CompositeCommands[name]; exists {
+		return Resolution{Name: name, Kind: KindComposite, Composite: composite}, true
+	}
+	if args, exists := e.commandMap[name]; exists {
+		return Resolution{Name: name, Kind: KindBasic, Args: append([]string(nil), args...)}, true
+	}
+	if e.isGitCommand(name) {
+		return Resolution{Name: name, Kind: KindGit, Args: []string{name}}, true
+	}
+	return Resolution{}, false
+}
@@ -0,0 +1,88 @@
+package xgit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNew_UnknownHandler(t *testing.T) {
+	cfg := &CommandConfig{
+		CompositeCommands: map[string]CompositeCommand{
+			"xx": {Steps: [][]string{{"status"}}, Handler: "no-such-handler"},
+		},
+	}
+	if _, err := New(cfg); err == nil || !strings.Contains(err.Error(), "未知的处理器: no-such-handler") {
+		t.Errorf("未知的处理器应该返回错误，得到 %v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	e := newTestEngine(t)
+
+	if res, ok := e.Resolve("cjfz"); !ok || res.Kind != KindBasic || !reflect.DeepEqual(res.Args, []string{"checkout", "-b"}) {
+		t.Errorf("cjfz 应该解析为基本命令 checkout -b，得到 %+v", res)
+	}
+	if res, ok := e.Resolve("kstj"); !ok || res.Kind != KindComposite || res.Composite.Handler != "quick-commit" {
+		t.Errorf("kstj 应该解析为复合命令，得到 %+v", res)
+	}
+	if res, ok := e.Resolve("status"); !ok || res.Kind != KindGit || !reflect.DeepEqual(res.Args, []string{"status"}) {
+		t.Errorf("status 应该解析为原生git命令，得到 %+v", res)
+	}
+	if _, ok := e.Resolve("invalidcommand"); ok {
+		t.Error("未知命令不应该解析成功")
+	}
+
+	// 修改返回的参数不应影响引擎
+	res, _ := e.Resolve("cjfz")
+	res.Args[0] = "switch"
+	if e.commandMap["cjfz"][0] != "checkout" {
+		t.Error("Resolve 应该返回参数的副本")
+	}
+}
+
+func TestEngineRun_CustomConfig(t *testing.T) {
+	e, err := New(&CommandConfig{
+		Commands:    map[string]Command{"ck": {Args: []string{"status", "-s"}, Category: "状态操作"}},
+		GitCommands: []string{"status"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := withRecordingRunner(t, e)
+	// 不在仓库中，不记录操作日志
+	runner.Stub(GitResult{ExitCode: 128}, "for-each-ref")
+
+	if code := e.Run([]string{"ck", "--branch"}); code != 0 {
+		t.Errorf("执行成功时退出码应该为 0，得到 %d", code)
+	}
+	if want := []string{"git for-each-ref '--format=%(refname) %(objectname) %(HEAD)' refs/heads refs/stash", "git status -s --branch"}; !reflect.DeepEqual(runner.Commands(), want) {
+		t.Errorf("应该执行 %v，得到 %v", want, runner.Commands())
+	}
+}
+
+func TestEngineRun_ExitCode(t *testing.T) {
+	e := newTestEngine(t)
+	runner := withRecordingRunner(t, e)
+	runner.Stub(GitResult{ExitCode: 1})
+
+	if code := e.Run([]string{"zt"}); code != 1 {
+		t.Errorf("git失败时应该返回git的退出码，得到 %d", code)
+	}
+	if code := e.Run([]string{"invalidcommand"}); code == 0 {
+		t.Error("未知命令的退出码不应该为 0")
+	}
+}
+
+func TestEngineRun_FlagsAreScoped(t *testing.T) {
+	e := newTestEngine(t)
+	runner := withRecordingRunner(t, e)
+
+	output := captureOutput(e, func() { e.Run([]string{"-n", "-y", "zt"}) })
+	if output != "git status\n" || len(runner.Calls) != 0 {
+		t.Errorf("预演模式不应该执行git，输出 %q，调用 %v", output, runner.Commands())
+	}
+	if e.DryRun || e.AssumeYes {
+		t.Error("全局选项只应该对本次执行有效")
+	}
+}
@@ -0,0 +1,196 @@
+package xgit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 未知命令，提示信息已经输出
+var ErrUnknownCommand = errors.New("未知命令")
+
+// 参数不正确，错误和用法已经输出
+var ErrInvalidArgs = errors.New("参数不正确")
+
+// Execute 执行拼音命令、复合命令或原生git命令，未知命令会给出建议或自动纠正。
+// 返回的错误已经向用户报告过，调用方只需据此决定退出码
+func (e *Engine) Execute(command string, args []string) error {
+	resolved, ok := e.Resolve(command)
+	if !ok {
+		// 按唯一前缀展开缩写
+		full, err := e.completePrefix(command)
+		var ambiguous *AmbiguousCommandError
+		if errors.As(err, &ambiguous) {
+			e.reportAmbiguousCommand(ambiguous)
+			return err
+		}
+		if full != "" {
+			return e.Execute(full, args)
+		}
+		if target, ok := e.reportUnknownCommand(command); ok {
+			return e.Execute(target, args)
+		}
+		return ErrUnknownCommand
+	}
+
+	if resolved.Kind == KindComposite {
+		return e.executeCompositeCommand(resolved.Name, resolved.Composite.Steps, args)
+	}
+	return e.executeGitCommand(append(resolved.Args, args...))
+}
+
+// 执行复合命令并报告结果
+func (e *Engine) executeCompositeCommand(cmdName string, commands [][]string, args []string) error {
+	fmt.Fprintf(e.Stdout, "执行复合命令: %s\n", cmdName)
+
+	spec := e.config.CompositeCommands[cmdName]
+	if err := e.runCompositeCommand(cmdName, spec, commands, args); err != nil {
+		e.reportCompositeError(cmdName, spec, err)
+		return err
+	}
+
+	if !e.DryRun {
+		fmt.Fprintf(e.Stdout, "✅ 复合命令 %s 完成！\n", cmdName)
+	}
+	return nil
+}
+
+// 执行复合命令：交给处理器，或按顺序执行配置中的每个步骤
+func (e *Engine) runCompositeCommand(cmdName string, spec CompositeCommand, commands [][]string, args []string) error {
+	if len(commands) == 0 {
+		return fmt.Errorf("未实现的复合命令: %s", cmdName)
+	}
+
+	if spec.Handler != "" {
+		handler, exists := compositeHandlers[spec.Handler]
+		if !exists {
+			return fmt.Errorf("复合命令 %s 使用了未知的处理器: %s", cmdName, spec.Handler)
+		}
+		return handler(e, cmdName, spec, args)
+	}
+
+	steps, err := expandSteps(commands, args, e.currentBranch)
+	if err != nil {
+		return err
+	}
+
+	for i, step := range steps {
+		if !e.DryRun {
+			fmt.Fprintf(e.Stdout, "→ [%d/%d] %s\n", i+1, len(steps), formatGitCommand(step))
+		}
+		if err := e.executeGitCommandWithError(step); err != nil {
+			return newStepError(cmdName, i+1, len(steps), step, err)
+		}
+	}
+	return nil
+}
+
+// 报告复合命令的失败原因
+func (e *Engine) reportCompositeError(cmdName string, spec CompositeCommand, err error) {
+	var stepErr *StepError
+	switch {
+	case errors.As(err, &stepErr):
+		fmt.Fprintf(e.Stdout, "✗ 步骤 %d/%d 失败: %s（退出码 %d）\n", stepErr.Step, stepErr.Total, formatGitCommand(stepErr.Args), stepErr.ExitCode)
+		fmt.Fprintf(e.Stdout, "复合命令 %s 未完成: 已完成 %d 步，失败 1 步，跳过 %d 步\n", cmdName, stepErr.Step-1, stepErr.Total-stepErr.Step)
+	case isArgError(err):
+		e.printCompositeArgError(cmdName, spec, err)
+	default:
+		fmt.Fprintf(e.Stdout, "错误: %v\n", err)
+	}
+}
+
+// 根据错误决定进程退出码：git失败时沿用git的退出码，其他错误为 1
+func ExitCode(err error) int {
+	var stepErr *StepError
+	if errors.As(err, &stepErr) {
+		return stepErr.ExitCode
+	}
+	var exitErr *GitExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode > 0 {
+		return exitErr.ExitCode
+	}
+	return 1
+}
+
+// 获取当前分支名（尚无提交的分支也能获取）
+func (e *Engine) currentBranch() (string, error) {
+	branch, err := e.gitOutput("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("无法获取当前分支（可能处于分离头指针状态）: %v", err)
+	}
+	return branch, nil
+}
+
+// 执行只读的git查询命令并返回去掉首尾空白的输出，预演模式下同样会执行
+func (e *Engine) gitOutput(args ...string) (string, error) {
+	result, err := e.Runner.Run(GitCommand{Args: args, Dir: e.Dir})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(result.Output), nil
+}
+
+// 检查引用是否存在
+func (e *Engine) refExists(ref string) bool {
+	_, err := e.gitOutput("show-ref", "--verify", "--quiet", ref)
+	return err == nil
+}
+
+// 执行git命令，git本身的输出直接显示给用户
+func (e *Engine) executeGitCommand(args []string) error {
+	if e.DryRun {
+		fmt.Fprintln(e.Stdout, formatGitCommand(args))
+		return nil
+	}
+	if err := e.confirmDestructive(args); err != nil {
+		return err
+	}
+
+	_, err := e.Runner.Run(e.interactiveGitCommand(args))
+	var exitErr *GitExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		fmt.Fprintf(e.Stdout, "执行git命令时出错: %v\n", err)
+	}
+	return err
+}
+
+// 执行git命令并返回错误（用于复合命令的错误处理）
+func (e *Engine) executeGitCommandWithError(args []string) error {
+	if e.DryRun {
+		fmt.Fprintln(e.Stdout, formatGitCommand(args))
+		return nil
+	}
+	if err := e.confirmDestructive(args); err != nil {
+		return err
+	}
+
+	_, err := e.Runner.Run(e.interactiveGitCommand(args))
+	return err
+}
+
+// 直接连接到终端的git调用，git本身的输出和交互都交给用户
+func (e *Engine) interactiveGitCommand(args []string) GitCommand {
+	return GitCommand{Args: args, Dir: e.Dir, Stdin: e.Stdin, Stdout: e.Stdout, Stderr: e.Stderr}
+}
+
+// 将git参数格式化为可直接复制到shell执行的命令行
+func formatGitCommand(args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, "git")
+	for _, arg := range args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// 按POSIX shell规则为参数加引号，不需要时原样返回
+func shellQuote(arg string) string {
+	needsQuote := arg == "" ||
+		strings.ContainsAny(arg, " \t\n'\"\\$`!*?[]()<>|&;#") ||
+		strings.HasPrefix(arg, "~") ||
+		(strings.ContainsAny(arg, "{}") && (strings.Contains(arg, ",") || strings.Contains(arg, "..")))
+	if !needsQuote {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
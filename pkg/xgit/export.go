@@ -0,0 +1,353 @@
+package xgit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 导出的git别名文件名（位于用户配置目录）
+const exportFileName = "aliases.gitconfig"
+
+// 依赖 xgit 自身状态、无法用shell别名实现的处理器
+var unexportableHandlers = map[string]string{
+	"undo": "依赖 xgit 的操作日志",
+}
+
+// 其他处理器的复合命令按 steps 导出，shell 别名中缺少处理器提供的选项和检查
+var handlerExportLimits = map[string]string{
+	"quick-commit": "路径参数、-a、--no-push、--amend 和没有上游分支时的 push -u",
+	"setup-remote": "--name、--no-check、地址检查和更新已有的远程仓库",
+	"sync-branch":  "未提交更改的检查、自动创建跟踪分支和分叉检测",
+}
+
+// git别名的名称只能包含字母、数字和 -，并以字母开头
+var gitAliasNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*$`)
+
+// 导出文件的默认路径
+func exportPath() (string, error) {
+	dir := userConfigDir()
+	if dir == "" {
+		return "", errors.New("无法确定用户配置目录")
+	}
+	return filepath.Join(dir, exportFileName), nil
+}
+
+// 将命令表导出为git别名：xgit bm dc [--remove] [文件]
+func (e *Engine) exportAliases(args []string) error {
+	remove := false
+	var path string
+	for _, arg := range args {
+		switch {
+		case arg == "--remove" || arg == "-r":
+			remove = true
+		case strings.HasPrefix(arg, "-") || path != "":
+			return fmt.Errorf("无法识别的参数: %s\n用法: xgit bm dc [--remove] [文件]", arg)
+		default:
+			path = arg
+		}
+	}
+	if path == "" {
+		var err error
+		if path, err = exportPath(); err != nil {
+			return err
+		}
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	if remove {
+		return e.removeExportedAliases(path)
+	}
+
+	aliases, skipped, limited := e.exportableAliases()
+	content := renderAliasFile(aliases)
+	old, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	included, err := e.includesFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, note := range skipped {
+		fmt.Fprintf(e.Stdout, "跳过 %s\n", note)
+	}
+	for _, note := range limited {
+		fmt.Fprintf(e.Stdout, "注意 %s\n", note)
+	}
+
+	if e.DryRun {
+		diff := lineDiff(string(old), content)
+		if len(diff) == 0 {
+			fmt.Fprintf(e.Stdout, "%s 已是最新\n", path)
+		} else {
+			fmt.Fprintf(e.Stdout, "将修改 %s:\n", path)
+			for _, line := range diff {
+				fmt.Fprintln(e.Stdout, line)
+			}
+		}
+		if !included {
+			fmt.Fprintf(e.Stdout, "将在全局git配置中添加 include.path = %s\n", path)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return err
+	}
+	if !included {
+		if _, err := e.Runner.Run(GitCommand{Args: []string{"config", "--global", "--add", "include.path", path}, Dir: e.Dir}); err != nil {
+			return fmt.Errorf("无法在全局git配置中添加 include.path: %v", err)
+		}
+	}
+	fmt.Fprintf(e.Stdout, "已导出 %d 个git别名到 %s\n", len(aliases), path)
+	if !included {
+		fmt.Fprintln(e.Stdout, "已在全局git配置中引用该文件")
+	}
+	fmt.Fprintln(e.Stdout, "删除导出的别名: xgit bm dc --remove")
+	return nil
+}
+
+// 删除导出文件及全局git配置中对它的引用
+func (e *Engine) removeExportedAliases(path string) error {
+	_, statErr := os.Stat(path)
+	exists := statErr == nil
+	included, err := e.includesFile(path)
+	if err != nil {
+		return err
+	}
+	if !exists && !included {
+		fmt.Fprintf(e.Stdout, "没有导出的别名: %s\n", path)
+		return nil
+	}
+
+	if e.DryRun {
+		if exists {
+			fmt.Fprintf(e.Stdout, "将删除 %s\n", path)
+		}
+		if included {
+			fmt.Fprintf(e.Stdout, "将从全局git配置中移除 include.path = %s\n", path)
+		}
+		return nil
+	}
+
+	if included {
+		pattern := "^" + regexp.QuoteMeta(path) + "$"
+		if _, err := e.Runner.Run(GitCommand{Args: []string{"config", "--global", "--unset-all", "include.path", pattern}, Dir: e.Dir}); err != nil {
+			return fmt.Errorf("无法从全局git配置中移除 include.path: %v", err)
+		}
+	}
+	if exists {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(e.Stdout, "已删除导出的别名: %s\n", path)
+	return nil
+}
+
+// 全局git配置是否已经引用了 path
+func (e *Engine) includesFile(path string) (bool, error) {
+	output, err := e.gitOutput("config", "--global", "--get-all", "include.path")
+	var exitErr *GitExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode == 1 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("无法读取全局git配置: %v", err)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if line == path {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// 按帮助中的顺序列出可以导出的别名、被跳过的命令和原因，
+// 以及导出后缺少处理器功能的命令。来自git配置的别名本身已经是git别名，不再导出
+func (e *Engine) exportableAliases() (aliases []gitAlias, skipped, limited []string) {
+	for _, category := range e.categoryOrder {
+		for _, name := range e.commandCategories[category] {
+			if e.isGitAlias(name) {
+				continue
+			}
+			if !gitAliasNamePattern.MatchString(name) {
+				skipped = append(skipped, name+": 不是有效的git别名名称")
+				continue
+			}
+			if args, exists := e.commandMap[name]; exists {
+				aliases = append(aliases, gitAlias{Name: name, Value: joinAliasArgs(args)})
+				continue
+			}
+
+			spec := e.config.CompositeCommands[name]
+			if reason, exists := unexportableHandlers[spec.Handler]; exists {
+				skipped = append(skipped, name+": "+reason)
+				continue
+			}
+			if lost, exists := handlerExportLimits[spec.Handler]; exists {
+				limited = append(limited, fmt.Sprintf("%s: 导出的别名只按步骤执行，不支持%s", name, lost))
+			}
+			aliases = append(aliases, gitAlias{Name: name, Value: compositeShellAlias(spec.Steps)})
+		}
+	}
+	return aliases, skipped, limited
+}
+
+// 将git参数拼接为git别名的值，git会按shell的引号规则拆分
+func joinAliasArgs(args []string) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = shellQuote(arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// 将复合命令的步骤转换为shell别名：步骤之间用 && 连接，
+// 占位符转换为shell的位置参数，{branch} 在执行时查询当前分支
+func compositeShellAlias(steps [][]string) string {
+	maxIndex, usesRest := placeholderUsage(steps)
+	// 同时使用位置参数和 {args} 时，先保存位置参数，再移除它们
+	saved := usesRest && maxIndex > 0
+	param := func(n int) string {
+		if saved {
+			return "xgit_" + strconv.Itoa(n)
+		}
+		return strconv.Itoa(n)
+	}
+
+	var body []string
+	if saved {
+		var preamble []string
+		for n := 1; n <= maxIndex; n++ {
+			preamble = append(preamble, fmt.Sprintf(`%s="$%d"`, param(n), n))
+		}
+		preamble = append(preamble, fmt.Sprintf("shift $(($# < %d ? $# : %d))", maxIndex, maxIndex))
+		body = append(body, strings.Join(preamble, "; "))
+	}
+
+	commands := make([]string, 0, len(steps))
+	for _, step := range steps {
+		words := []string{"git"}
+		for _, arg := range step {
+			if arg == restPlaceholder {
+				words = append(words, `"$@"`)
+				continue
+			}
+			words = append(words, shellWord(arg, param))
+		}
+		commands = append(commands, strings.Join(words, " "))
+	}
+	body = append(body, strings.Join(commands, " && "))
+
+	return "!f() { " + strings.Join(body, "; ") + "; }; f"
+}
+
+// 将含有占位符的参数转换为一个shell单词
+func shellWord(arg string, param func(int) string) string {
+	var word strings.Builder
+	last := 0
+	literal := func(s string) {
+		if s != "" {
+			word.WriteString(shellQuote(s))
+		}
+	}
+	for _, m := range findPlaceholders(arg) {
+		literal(arg[last:m[0]])
+		last = m[1]
+		if m[2] < 0 {
+			word.WriteString(`"$(git symbolic-ref --short HEAD)"`)
+			continue
+		}
+		n, _ := strconv.Atoi(arg[m[2]:m[3]])
+		if m[4] >= 0 {
+			fmt.Fprintf(&word, `"${%s:-%s}"`, param(n), doubleQuoteEscape(arg[m[4]:m[5]]))
+		} else {
+			fmt.Fprintf(&word, `"${%s:?缺少第 %d 个参数}"`, param(n), n)
+		}
+	}
+	literal(arg[last:])
+	if word.Len() == 0 {
+		return "''"
+	}
+	return word.String()
+}
+
+// 转义shell双引号中的特殊字符
+func doubleQuoteEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`").Replace(s)
+}
+
+// 将字符串写成gitconfig文件中带引号的值
+func gitConfigValue(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s) + `"`
+}
+
+// 生成导出文件的内容
+func renderAliasFile(aliases []gitAlias) string {
+	var b strings.Builder
+	b.WriteString("# 由 xgit bm dc 生成，重新导出时会覆盖，请不要手动修改\n")
+	b.WriteString("# 删除: xgit bm dc --remove\n")
+	b.WriteString("[alias]\n")
+	for _, alias := range aliases {
+		fmt.Fprintf(&b, "\t%s = %s\n", alias.Name, gitConfigValue(alias.Value))
+	}
+	return b.String()
+}
+
+// 逐行比较新旧内容，返回以 - 和 + 标记的差异行
+func lineDiff(old, new string) []string {
+	a := strings.Split(strings.TrimSuffix(old, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(new, "\n"), "\n")
+	if old == "" {
+		a = nil
+	}
+	if new == "" {
+		b = nil
+	}
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+	return diff
+}
@@ -0,0 +1,204 @@
+package xgit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompositeShellAlias(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    [][]string
+		expected string
+	}{
+		{
+			"没有占位符",
+			[][]string{{"fetch"}, {"status", "-s"}},
+			`!f() { git fetch && git status -s; }; f`,
+		},
+		{
+			"位置参数和默认值",
+			[][]string{{"fetch", "{2:origin}"}, {"checkout", "{1}"}},
+			`!f() { git fetch "${2:-origin}" && git checkout "${1:?缺少第 1 个参数}"; }; f`,
+		},
+		{
+			"嵌在参数中的占位符和当前分支",
+			[][]string{{"push", "origin", "{branch}:review/{1}"}, {"commit", "-m", "it's {1:a \"b\" $c}"}},
+			`!f() { git push origin "$(git symbolic-ref --short HEAD)":review/"${1:?缺少第 1 个参数}" && git commit -m 'it'\''s '"${1:-a \"b\" \$c}"; }; f`,
+		},
+		{
+			"其余参数",
+			[][]string{{"log", "{args}"}},
+			`!f() { git log "$@"; }; f`,
+		},
+		{
+			"位置参数与其余参数",
+			[][]string{{"commit", "-m", "{1}", "{args}"}},
+			`!f() { xgit_1="$1"; shift $(($# < 1 ? $# : 1)); git commit -m "${xgit_1:?缺少第 1 个参数}" "$@"; }; f`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := compositeShellAlias(tt.steps); result != tt.expected {
+				t.Errorf("结果不正确\n期望: %s\n得到: %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+// 通过shell执行导出的别名（去掉开头的 !），把git替换为打印参数的函数
+func runShellAlias(t *testing.T, alias string, args ...string) string {
+	t.Helper()
+	script := `git() { printf '[%s]' "$@"; echo; }; ` + strings.TrimPrefix(alias, "!") + ` "$@"`
+	output, err := exec.Command("sh", append([]string{"-c", script, "sh"}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("执行失败: %v\n%s", err, output)
+	}
+	return string(output)
+}
+
+func TestCompositeShellAlias_Shell(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("需要 sh")
+	}
+
+	alias := compositeShellAlias([][]string{{"commit", "-m", "{1}", "{args}"}, {"push", "{2:origin}"}})
+	if output := runShellAlias(t, alias, "a b", "up", "-v"); output != "[commit][-m][a b][-v]\n[push][up]\n" {
+		t.Errorf("参数绑定不正确:\n%s", output)
+	}
+	if output := runShellAlias(t, alias, "msg"); output != "[commit][-m][msg]\n[push][origin]\n" {
+		t.Errorf("默认值不正确:\n%s", output)
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	old := "a\nb\nc\n"
+	new := "a\nc\nd\n"
+	if diff := lineDiff(old, new); !reflect.DeepEqual(diff, []string{"- b", "+ d"}) {
+		t.Errorf("差异不正确: %q", diff)
+	}
+	if diff := lineDiff("", "a\n"); !reflect.DeepEqual(diff, []string{"+ a"}) {
+		t.Errorf("新文件的差异不正确: %q", diff)
+	}
+	if diff := lineDiff(old, old); len(diff) != 0 {
+		t.Errorf("相同的内容不应该有差异: %q", diff)
+	}
+}
+
+func TestExportableAliases(t *testing.T) {
+	e := newTestEngine(t)
+	aliases, skipped, limited := e.exportableAliases()
+
+	values := make(map[string]string)
+	for _, alias := range aliases {
+		values[alias.Name] = alias.Value
+	}
+	if values["ts"] != "push" || values["cjfz"] != "checkout -b" {
+		t.Errorf("基本命令应该导出为对应的git参数，得到 ts=%q cjfz=%q", values["ts"], values["cjfz"])
+	}
+	if !strings.HasPrefix(values["kstj"], "!f() { git add . && ") {
+		t.Errorf("复合命令应该导出为shell别名，得到 %q", values["kstj"])
+	}
+	if _, exists := values["cx"]; exists || !reflect.DeepEqual(skipped, []string{"cx: 依赖 xgit 的操作日志"}) {
+		t.Errorf("撤销命令不能导出，跳过的命令: %v", skipped)
+	}
+	if len(aliases) != len(e.commandMap)+len(e.compositeCommands)-1 {
+		t.Errorf("应该导出所有其他命令，得到 %d 个", len(aliases))
+	}
+
+	// 使用处理器的复合命令按步骤导出，并说明缺少的选项
+	if len(limited) != 3 || !strings.HasPrefix(limited[0], "kstj: 导出的别名只按步骤执行，不支持路径参数、-a、--no-push、--amend") {
+		t.Errorf("应该说明 kstj、ycsh、tbfz 导出后缺少的功能，得到 %v", limited)
+	}
+}
+
+func TestExportAliases_GitConfigRoundTrip(t *testing.T) {
+	isolateGit(t)
+	path := filepath.Join(t.TempDir(), "aliases.gitconfig")
+	aliases := []gitAlias{
+		{Name: "kstj", Value: compositeShellAlias([][]string{{"commit", "-m", "{1:a \"b\" \\ c}"}})},
+		{Name: "lg", Value: joinAliasArgs([]string{"log", "--format=%h %s"})},
+	}
+	if err := os.WriteFile(path, []byte(renderAliasFile(aliases)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, alias := range aliases {
+		output, err := exec.Command("git", "config", "--file", path, "alias."+alias.Name).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSuffix(string(output), "\n"); got != alias.Value {
+			t.Errorf("git 读到的 %s 不正确\n期望: %s\n得到: %s", alias.Name, alias.Value, got)
+		}
+	}
+}
+
+func TestExportAliases(t *testing.T) {
+	e := newTestEngine(t)
+	runner := withRecordingRunner(t, e)
+	runner.Stub(GitResult{ExitCode: 1}, "config", "--global", "--get-all")
+	path := filepath.Join(t.TempDir(), "git", "aliases.gitconfig")
+
+	var err error
+	output := captureOutput(e, func() { err = e.aliasCommand([]string{"dc", path}) })
+	if err != nil {
+		t.Fatalf("导出失败: %v\n%s", err, output)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "[alias]\n") || !strings.Contains(string(data), "\tts = \"push\"\n") {
+		t.Errorf("导出文件的内容不正确:\n%s", data)
+	}
+	if !strings.Contains(string(data), "\tkstj = \"!f() { git add . && ") {
+		t.Errorf("使用处理器的复合命令应该按步骤导出:\n%s", data)
+	}
+	if !strings.Contains(output, "注意 kstj: 导出的别名只按步骤执行") || !strings.Contains(output, "跳过 cx: 依赖 xgit 的操作日志") {
+		t.Errorf("应该说明导出后缺少的功能和跳过的命令:\n%s", output)
+	}
+	if !strings.Contains(runner.Commands()[1], "git config --global --add include.path "+path) {
+		t.Errorf("应该在全局git配置中引用导出文件，得到 %v", runner.Commands())
+	}
+
+	// 已经引用时不再重复添加，预演只显示差异
+	runner = withRecordingRunner(t, e)
+	runner.Stub(GitResult{Output: path + "\n"}, "config", "--global", "--get-all")
+	e.config.Commands["ts"] = Command{Args: []string{"push", "--follow-tags"}, Category: "远程操作"}
+	e.generateMappings()
+	e.DryRun = true
+	output = captureOutput(e, func() { err = e.aliasCommand([]string{"dc", path}) })
+	if err != nil || !strings.Contains(output, "- \tts = \"push\"\n+ \tts = \"push --follow-tags\"\n") || strings.Contains(output, "include.path") {
+		t.Errorf("预演输出不正确: %v\n%s", err, output)
+	}
+	if len(runner.Calls) != 1 {
+		t.Errorf("预演时只应该查询git配置，得到 %v", runner.Commands())
+	}
+}
+
+func TestExportAliases_Remove(t *testing.T) {
+	e := newTestEngine(t)
+	runner := withRecordingRunner(t, e)
+	path := filepath.Join(t.TempDir(), "aliases.gitconfig")
+	runner.Stub(GitResult{Output: "/other.gitconfig\n" + path + "\n"}, "config", "--global", "--get-all")
+	writeConfigFile(t, path, "[alias]\n")
+
+	var err error
+	output := captureOutput(e, func() { err = e.aliasCommand([]string{"dc", "--remove", path}) })
+	if err != nil {
+		t.Fatalf("删除失败: %v\n%s", err, output)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("导出文件应该被删除")
+	}
+	want := "git config --global --unset-all include.path '^" + strings.ReplaceAll(path, ".", `\.`) + "$'"
+	if commands := runner.Commands(); commands[len(commands)-1] != want {
+		t.Errorf("应该移除 include.path，期望 %s，得到 %v", want, commands)
+	}
+}
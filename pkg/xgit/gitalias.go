@@ -0,0 +1,252 @@
+package xgit
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+)
+
+// git配置中的别名所在的分类
+const gitAliasCategory = "Git 别名"
+
+// git配置 [alias] 中的一个别名
+type gitAlias struct {
+	Name  string
+	Value string
+}
+
+// 以 ! 开头的别名由shell执行
+func (a gitAlias) isShell() bool {
+	return strings.HasPrefix(a.Value, "!")
+}
+
+func (a gitAlias) description() string {
+	return "Git 别名 → " + a.Value
+}
+
+// 执行别名时的git参数：普通别名展开为对应的git参数，
+// shell别名仍然交给git执行
+func (a gitAlias) args() ([]string, error) {
+	if a.isShell() {
+		return []string{a.Name}, nil
+	}
+	return splitAliasValue(a.Value)
+}
+
+// 导入到xgit配置时的git参数：shell别名通过 -c 临时定义，不再依赖git配置
+func (a gitAlias) importArgs() ([]string, error) {
+	if a.isShell() {
+		return []string{"-c", "alias." + a.Name + "=" + a.Value, a.Name}, nil
+	}
+	return splitAliasValue(a.Value)
+}
+
+// 按git的规则拆分别名的值：空白分隔参数，支持单引号、双引号和反斜杠转义
+func splitAliasValue(value string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(value)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\' && quote != '\'':
+			if i+1 >= len(runes) {
+				return nil, fmt.Errorf("别名 %q 以反斜杠结尾", value)
+			}
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("别名 %q 中的引号没有闭合", value)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("别名的值为空")
+	}
+	return args, nil
+}
+
+// 读取git配置（包括全局和当前仓库）中的别名，按名称排序；
+// 同名的别名以后读到的（优先级更高的）为准
+func (e *Engine) readGitAliases() ([]gitAlias, error) {
+	output, err := e.gitOutput("config", "-z", "--get-regexp", `^alias\.`)
+	var exitErr *GitExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode == 1 {
+		// 没有匹配的配置项
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, entry := range strings.Split(output, "\x00") {
+		key, value, found := strings.Cut(entry, "\n")
+		name := strings.TrimPrefix(key, "alias.")
+		if !found || name == key || name == "" || strings.TrimSpace(value) == "" {
+			continue
+		}
+		values[name] = value
+	}
+
+	aliases := make([]gitAlias, 0, len(values))
+	for _, name := range sortedKeys(values) {
+		aliases = append(aliases, gitAlias{Name: name, Value: values[name]})
+	}
+	return aliases, nil
+}
+
+// 将git配置中的别名加入命令表，放在"Git 别名"分类中。
+// 与xgit命令、原生git命令重名或无法解析的别名被忽略；读取失败时不影响其他命令
+func (e *Engine) loadGitAliases() {
+	aliases, err := e.readGitAliases()
+	if err != nil || len(aliases) == 0 {
+		return
+	}
+
+	cfg := *e.config
+	cfg.Commands = maps.Clone(e.config.Commands)
+	if cfg.Commands == nil {
+		cfg.Commands = make(map[string]Command)
+	}
+	e.gitAliases = make(map[string]gitAlias)
+	for _, alias := range aliases {
+		if !e.gitAliasUsable(alias.Name) {
+			continue
+		}
+		args, err := alias.args()
+		if err != nil {
+			continue
+		}
+		cfg.Commands[alias.Name] = Command{Args: args, Description: alias.description(), Category: gitAliasCategory}
+		e.gitAliases[alias.Name] = alias
+	}
+
+	e.config = &cfg
+	e.generateMappings()
+}
+
+// git别名能否加入命令表：不能遮蔽xgit自身的命令、配置中的命令和原生git命令
+func (e *Engine) gitAliasUsable(name string) bool {
+	if isBuiltinCommand(name) || e.isGitCommand(name) {
+		return false
+	}
+	_, isCommand := e.config.Commands[name]
+	_, isComposite := e.config.CompositeCommands[name]
+	_, isAlternate := e.alternateNames[normalizeName(name)]
+	return !isCommand && !isComposite && !isAlternate
+}
+
+// 命令是否来自git配置中的别名
+func (e *Engine) isGitAlias(name string) bool {
+	_, exists := e.gitAliases[name]
+	return exists
+}
+
+// 将git别名导入用户配置：xgit bm dr [-f] [别名...]
+func (e *Engine) importGitAliases(args []string) error {
+	force := false
+	var names []string
+	for _, arg := range args {
+		switch {
+		case arg == "-f" || arg == "--force":
+			force = true
+		case strings.HasPrefix(arg, "-"):
+			return fmt.Errorf("无法识别的参数: %s\n用法: xgit bm dr [-f] [别名...]", arg)
+		default:
+			names = append(names, arg)
+		}
+	}
+
+	aliases, err := e.readGitAliases()
+	if err != nil {
+		return fmt.Errorf("无法读取git别名: %v", err)
+	}
+	if len(names) > 0 {
+		selected := make([]gitAlias, 0, len(names))
+		for _, name := range names {
+			i := sort.Search(len(aliases), func(i int) bool { return aliases[i].Name >= name })
+			if i == len(aliases) || aliases[i].Name != name {
+				return fmt.Errorf("git配置中没有别名 %s", name)
+			}
+			selected = append(selected, aliases[i])
+		}
+		aliases = selected
+	}
+	if len(aliases) == 0 {
+		fmt.Fprintln(e.Stdout, "git配置中没有别名")
+		return nil
+	}
+
+	path, data, cfg, err := readUserConfig()
+	if err != nil {
+		return err
+	}
+
+	imported := 0
+	for _, alias := range aliases {
+		if err := e.checkAliasName(alias.Name, force); err != nil {
+			fmt.Fprintf(e.Stdout, "跳过 %s: %v\n", alias.Name, strings.ReplaceAll(err.Error(), "\n", "，"))
+			continue
+		}
+		gitArgs, err := alias.importArgs()
+		if err != nil {
+			fmt.Fprintf(e.Stdout, "跳过 %s: %v\n", alias.Name, err)
+			continue
+		}
+
+		cmd := Command{Args: gitArgs, Description: alias.description(), Category: customCategory}
+		if data, err = putUserAlias(data, cfg, alias.Name, cmd); err != nil {
+			return fmt.Errorf("无法修改 %s: %v", path, err)
+		}
+		imported++
+		fmt.Fprintf(e.Stdout, "导入 %s → %s\n", alias.Name, alias.Value)
+	}
+
+	if imported == 0 {
+		fmt.Fprintln(e.Stdout, "没有导入任何别名")
+		return nil
+	}
+	if e.DryRun {
+		fmt.Fprintf(e.Stdout, "将在 %s 中添加 %d 个别名\n", path, imported)
+		return nil
+	}
+	if err := writeUserConfig(path, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.Stdout, "已导入 %d 个git别名到 %s\n", imported, path)
+	return nil
+}
@@ -0,0 +1,188 @@
+package xgit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// git config -z --get-regexp 的输出
+const gitAliasOutput = "alias.lg\nlog --oneline --format='%h %s'\x00" +
+	"alias.hi\n!echo hi\x00" +
+	"alias.ts\nstatus\x00" +
+	"alias.status\nstatus -s\x00" +
+	"alias.lg\nlog --oneline -5\x00" +
+	"alias.bad\nlog \"unterminated\x00"
+
+// 使用预设git别名的引擎，返回记录git调用的 runner
+func gitAliasEngine(t *testing.T) (*Engine, *RecordingRunner) {
+	t.Helper()
+	e := newTestEngine(t)
+	e.GitAliases = true
+	runner := withRecordingRunner(t, e)
+	runner.Stub(GitResult{Output: gitAliasOutput}, "config", "-z", "--get-regexp")
+	// 不在仓库中，不记录操作日志
+	runner.Stub(GitResult{ExitCode: 128}, "for-each-ref")
+	return e, runner
+}
+
+func TestSplitAliasValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"log --oneline", []string{"log", "--oneline"}},
+		{"  commit   -m  'a b' ", []string{"commit", "-m", "a b"}},
+		{`log "--format=%h \"%s\""`, []string{"log", `--format=%h "%s"`}},
+		{`log --format=a\ b ''`, []string{"log", "--format=a b", ""}},
+		{"log 'it'\"'\"s", []string{"log", "it's"}},
+	}
+	for _, tt := range tests {
+		result, err := splitAliasValue(tt.value)
+		if err != nil || !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("splitAliasValue(%q) = %q, %v，期望 %q", tt.value, result, err, tt.expected)
+		}
+	}
+
+	for _, value := range []string{`log "a`, "log 'a", `log \`, "  "} {
+		if _, err := splitAliasValue(value); err == nil {
+			t.Errorf("splitAliasValue(%q) 应该返回错误", value)
+		}
+	}
+}
+
+func TestReadGitAliases(t *testing.T) {
+	e, _ := gitAliasEngine(t)
+	aliases, err := e.readGitAliases()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []gitAlias{
+		{"bad", `log "unterminated`},
+		{"hi", "!echo hi"},
+		{"lg", "log --oneline -5"},
+		{"status", "status -s"},
+		{"ts", "status"},
+	}
+	if !reflect.DeepEqual(aliases, want) {
+		t.Errorf("应该按名称排序并以后读到的为准，得到 %v", aliases)
+	}
+
+	// 没有配置别名时 git config 的退出码为 1
+	runner := withRecordingRunner(t, e)
+	runner.Stub(GitResult{ExitCode: 1}, "config")
+	if aliases, err := e.readGitAliases(); err != nil || len(aliases) != 0 {
+		t.Errorf("没有别名时应该返回空列表，得到 %v, %v", aliases, err)
+	}
+}
+
+func TestGitAliases_Execute(t *testing.T) {
+	e, runner := gitAliasEngine(t)
+
+	if code := e.Run([]string{"lg", "-p"}); code != 0 {
+		t.Fatalf("执行git别名失败，退出码 %d", code)
+	}
+	if code := e.Run([]string{"hi", "there"}); code != 0 {
+		t.Fatalf("执行shell别名失败，退出码 %d", code)
+	}
+
+	var executed []string
+	for _, command := range runner.Commands() {
+		if !strings.HasPrefix(command, "git config") && !strings.HasPrefix(command, "git for-each-ref") {
+			executed = append(executed, command)
+		}
+	}
+	want := []string{"git log --oneline -5 -p", "git hi there"}
+	if !reflect.DeepEqual(executed, want) {
+		t.Errorf("普通别名应该展开，shell别名应该交给git执行，期望 %v，得到 %v", want, executed)
+	}
+}
+
+func TestGitAliases_Conflicts(t *testing.T) {
+	e, _ := gitAliasEngine(t)
+	run := *e
+	run.loadGitAliases()
+
+	if res, _ := run.Resolve("ts"); !reflect.DeepEqual(res.Args, []string{"push"}) {
+		t.Errorf("xgit 的命令应该优先于同名的git别名，得到 %v", res.Args)
+	}
+	if res, _ := run.Resolve("status"); res.Kind != KindGit {
+		t.Errorf("与原生git命令同名的别名应该被忽略，得到 %+v", res)
+	}
+	if _, ok := run.Resolve("bad"); ok {
+		t.Error("无法解析的别名应该被忽略")
+	}
+	if _, ok := e.Resolve("lg"); ok {
+		t.Error("加载git别名不应该修改原来的引擎")
+	}
+}
+
+func TestGitAliases_Help(t *testing.T) {
+	e, _ := gitAliasEngine(t)
+	output := captureOutput(e, func() { e.Run([]string{"bz"}) })
+
+	section := output[strings.Index(output, "【Git 别名】"):]
+	for _, element := range []string{"hi     Git 别名 → !echo hi", "lg     Git 别名 → log --oneline -5"} {
+		if !strings.Contains(section, element) {
+			t.Errorf("帮助中缺少 %q\n实际输出:\n%s", element, output)
+		}
+	}
+	if strings.Contains(section, "ts     Git 别名") {
+		t.Error("被遮蔽的git别名不应该显示在帮助中")
+	}
+	if !strings.HasSuffix(strings.TrimSpace(section[:strings.Index(section, "使用 'xgit bz")]), "Git 别名 → log --oneline -5") {
+		t.Errorf("Git 别名应该是最后一个分类\n%s", output)
+	}
+}
+
+func TestImportGitAliases(t *testing.T) {
+	e, _ := gitAliasEngine(t)
+	path := isolateUserConfig(t)
+
+	// 已加载的git别名不算作已存在的别名
+	var code int
+	output := captureOutput(e, func() { code = e.Run([]string{"bm", "dr"}) })
+	if code != 0 {
+		t.Fatalf("导入失败，退出码 %d\n%s", code, output)
+	}
+	for _, element := range []string{"导入 hi → !echo hi", "导入 lg → log --oneline -5", "跳过 ts: 别名 ts 已存在", "跳过 status: status 是原生git命令", "跳过 bad:", "已导入 2 个git别名"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"-c", "alias.hi=!echo hi", "hi"}; !reflect.DeepEqual(cfg.Commands["hi"].Args, want) {
+		t.Errorf("shell别名应该通过 -c 定义，得到 %v", cfg.Commands["hi"].Args)
+	}
+	if want := []string{"log", "--oneline", "-5"}; !reflect.DeepEqual(cfg.Commands["lg"].Args, want) {
+		t.Errorf("普通别名应该展开为git参数，得到 %v", cfg.Commands["lg"].Args)
+	}
+	if cfg.Commands["lg"].Category != customCategory {
+		t.Errorf("导入的别名应该归入 %s，得到 %s", customCategory, cfg.Commands["lg"].Category)
+	}
+}
+
+func TestImportGitAliases_Selected(t *testing.T) {
+	e, _ := gitAliasEngine(t)
+	path := isolateUserConfig(t)
+	e.DryRun = true
+
+	var err error
+	output := captureOutput(e, func() { err = e.aliasCommand([]string{"dr", "-f", "ts"}) })
+	if err != nil || !strings.Contains(output, "导入 ts → status") || !strings.Contains(output, "将在 "+path+" 中添加 1 个别名") {
+		t.Errorf("-f 应该允许覆盖 ts，得到 %v\n%s", err, output)
+	}
+	if strings.Contains(output, "lg") {
+		t.Errorf("只应该导入指定的别名\n%s", output)
+	}
+
+	output = captureOutput(e, func() { err = e.aliasCommand([]string{"dr", "nosuch"}) })
+	if err == nil || !strings.Contains(output, "git配置中没有别名 nosuch") {
+		t.Errorf("导入不存在的别名应该报错，得到 %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,305 @@
+package xgit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 用户取消了破坏性操作
+var ErrAborted = errors.New("操作已取消")
+
+// 破坏性操作及其将会丢失的内容
+type destructiveOp struct {
+	Summary string
+	Details []string
+	// 无法在本地确定会丢失什么时（如强制推送），即使 Details 为空也需要确认
+	AlwaysConfirm bool
+}
+
+// 识别会丢失工作成果的git参数，返回 nil 表示无需确认
+func (e *Engine) detectDestructive(args []string) *destructiveOp {
+	args = stripGitOptions(args)
+	if len(args) == 0 {
+		return nil
+	}
+
+	var op *destructiveOp
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "reset":
+		op = e.detectReset(rest)
+	case "checkout":
+		op = e.detectCheckout(rest)
+	case "push":
+		op = e.detectForcePush(rest)
+	case "clean":
+		op = e.detectClean(rest)
+	case "branch":
+		op = e.detectBranchDelete(rest)
+	}
+
+	if op == nil || (len(op.Details) == 0 && !op.AlwaysConfirm) {
+		return nil
+	}
+	return op
+}
+
+// 去掉子命令之前的git全局选项，如 -c key=value、-C dir
+func stripGitOptions(args []string) []string {
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if (args[0] == "-c" || args[0] == "-C") && len(args) > 1 {
+			args = args[2:]
+			continue
+		}
+		args = args[1:]
+	}
+	return args
+}
+
+// 分离选项和位置参数，"--" 之后的参数都是路径
+func splitArgs(args []string) (flags, positional, paths []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return flags, positional, args[i+1:]
+		}
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return flags, positional, nil
+}
+
+// 是否包含某个选项，短选项可以合并书写（如 -fd）
+func hasFlag(flags []string, long string, short byte) bool {
+	for _, flag := range flags {
+		if long != "" && flag == long {
+			return true
+		}
+		if short != 0 && len(flag) > 1 && flag[0] == '-' && flag[1] != '-' && strings.IndexByte(flag[1:], short) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// 将git输出按行拆分，去掉空行
+func outputLines(out string) []string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// git reset --hard：丢弃未提交的修改，目标之后的提交不再属于当前分支
+func (e *Engine) detectReset(args []string) *destructiveOp {
+	flags, positional, _ := splitArgs(args)
+	if !hasFlag(flags, "--hard", 0) {
+		return nil
+	}
+
+	op := &destructiveOp{Summary: "git reset --hard 会丢弃所有未提交的修改"}
+	if status, err := e.gitOutput("status", "--porcelain", "--untracked-files=no"); err == nil {
+		for _, line := range outputLines(status) {
+			op.Details = append(op.Details, "未提交的修改: "+strings.TrimSpace(line))
+		}
+	}
+	if len(positional) > 0 {
+		if commits, err := e.gitOutput("log", "--oneline", positional[0]+"..HEAD"); err == nil {
+			for _, line := range outputLines(commits) {
+				op.Details = append(op.Details, "将离开当前分支的提交: "+line)
+			}
+		}
+	}
+	return op
+}
+
+// git checkout -- <路径>、git checkout .、git checkout -f：用暂存区或目标提交的内容覆盖工作区的修改
+func (e *Engine) detectCheckout(args []string) *destructiveOp {
+	flags, positional, paths := splitArgs(args)
+	switch {
+	case paths != nil:
+	case len(positional) == 1 && positional[0] == ".":
+		paths = positional
+	case hasFlag(flags, "--force", 'f'):
+		paths = []string{"."}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	op := &destructiveOp{Summary: "git checkout 会覆盖以下文件中未暂存的修改"}
+	diffArgs := append([]string{"diff", "--name-only", "--"}, paths...)
+	if files, err := e.gitOutput(diffArgs...); err == nil {
+		for _, file := range outputLines(files) {
+			op.Details = append(op.Details, "将丢弃修改: "+file)
+		}
+	}
+	return op
+}
+
+// git push -f、--force-with-lease、--force-if-includes、+refspec：覆盖远程分支上本地没有的提交；
+// git push --mirror：让远程与本地完全一致，还会删除远程上本地没有的分支
+func (e *Engine) detectForcePush(args []string) *destructiveOp {
+	flags, positional, _ := splitArgs(args)
+	forced := hasFlag(flags, "--force", 'f')
+	mirror := false
+	for _, flag := range flags {
+		switch {
+		case flag == "--force-with-lease" || strings.HasPrefix(flag, "--force-with-lease="),
+			flag == "--force-if-includes":
+			forced = true
+		case flag == "--mirror":
+			mirror = true
+		}
+	}
+	for _, refspec := range positional {
+		if strings.HasPrefix(refspec, "+") {
+			forced = true
+		}
+	}
+	if !forced && !mirror {
+		return nil
+	}
+
+	op := &destructiveOp{
+		Summary:       "强制推送会覆盖远程分支，远程上本地没有的提交将会丢失",
+		AlwaysConfirm: true,
+	}
+	if mirror {
+		op.Summary = "镜像推送会强制覆盖远程的所有引用，远程上本地没有的分支、标签和提交将会丢失"
+	}
+	if commits, err := e.gitOutput("log", "--oneline", "HEAD..@{upstream}"); err == nil {
+		for _, line := range outputLines(commits) {
+			op.Details = append(op.Details, "远程上将被覆盖的提交: "+line)
+		}
+	}
+	return op
+}
+
+// git clean -f：删除未跟踪的文件
+func (e *Engine) detectClean(args []string) *destructiveOp {
+	flags, _, _ := splitArgs(args)
+	if !hasFlag(flags, "--force", 'f') || hasFlag(flags, "--dry-run", 'n') {
+		return nil
+	}
+
+	// 用 -n 预演同样的清理，列出将被删除的文件
+	preview := []string{"clean", "-n"}
+	for _, arg := range args {
+		switch {
+		case arg == "--force" || arg == "-f" || arg == "-i" || arg == "--interactive":
+			continue
+		case len(arg) > 1 && arg[0] == '-' && arg[1] != '-':
+			arg = strings.ReplaceAll(arg, "f", "")
+			if arg == "-" {
+				continue
+			}
+		}
+		preview = append(preview, arg)
+	}
+
+	op := &destructiveOp{Summary: "git clean 会永久删除未跟踪的文件，无法通过git找回"}
+	if out, err := e.gitOutput(preview...); err == nil {
+		for _, line := range outputLines(out) {
+			op.Details = append(op.Details, "将删除: "+strings.TrimPrefix(line, "Would remove "))
+		}
+	}
+	return op
+}
+
+// git branch -D：删除尚未合并的分支
+func (e *Engine) detectBranchDelete(args []string) *destructiveOp {
+	flags, positional, _ := splitArgs(args)
+	forceDelete := hasFlag(flags, "", 'D') ||
+		(hasFlag(flags, "--delete", 'd') && hasFlag(flags, "--force", 'f'))
+	if !forceDelete {
+		return nil
+	}
+
+	op := &destructiveOp{Summary: "强制删除分支，只在该分支上的提交将不再被任何分支引用"}
+	for _, branch := range positional {
+		// 排除要删除的分支本身，列出其他本地分支和远程分支都不包含的提交
+		commits, err := e.gitOutput("log", "--oneline", branch, "--not",
+			"--exclude="+branch, "--branches", "--remotes")
+		if err != nil {
+			continue
+		}
+		for _, line := range outputLines(commits) {
+			op.Details = append(op.Details, fmt.Sprintf("只在分支 %s 上的提交: %s", branch, line))
+		}
+	}
+	return op
+}
+
+// 是否需要确认破坏性操作，可以在配置中通过 confirm_destructive 关闭
+func (e *Engine) confirmationEnabled() bool {
+	if e.AssumeYes || e.DryRun {
+		return false
+	}
+	setting := e.config.Settings.ConfirmDestructive
+	return setting == nil || *setting
+}
+
+// 执行破坏性操作前展示将会丢失的内容并请求确认，用户拒绝时返回 ErrAborted
+func (e *Engine) confirmDestructive(args []string) error {
+	if !e.confirmationEnabled() {
+		return nil
+	}
+	op := e.detectDestructive(args)
+	if op == nil {
+		return nil
+	}
+
+	fmt.Fprintf(e.Stdout, "⚠️  危险操作: %s\n", formatGitCommand(args))
+	fmt.Fprintln(e.Stdout, op.Summary)
+	for _, detail := range op.Details {
+		fmt.Fprintf(e.Stdout, "  %s\n", detail)
+	}
+	if !e.askConfirmation("确定要继续吗？[y/N] ") {
+		return ErrAborted
+	}
+	return nil
+}
+
+// 显示提示并读取用户的回答，只有明确回答 y / yes / 是 才算同意
+func (e *Engine) askConfirmation(prompt string) bool {
+	fmt.Fprint(e.Stdout, prompt)
+	answer := e.readLine()
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "是":
+		return true
+	}
+	if answer == "" {
+		fmt.Fprintln(e.Stdout)
+	}
+	fmt.Fprintln(e.Stdout, "已取消")
+	return false
+}
+
+// Stdin 的缓冲读取器。一次执行中的多次确认共用它，否则前一次读取时
+// 缓冲的后续回答（如通过管道输入的多行回答）会丢失
+type stdinReader struct {
+	source io.Reader
+	reader *bufio.Reader
+}
+
+// 从 Stdin 读取一行，Stdin 被替换后重新建立缓冲
+func (e *Engine) readLine() string {
+	if e.stdin == nil {
+		e.stdin = &stdinReader{}
+	}
+	if e.stdin.reader == nil || e.stdin.source != e.Stdin {
+		e.stdin.source = e.Stdin
+		e.stdin.reader = bufio.NewReader(e.Stdin)
+	}
+	line, _ := e.stdin.reader.ReadString('\n')
+	return line
+}
@@ -0,0 +1,258 @@
+package xgit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 创建一个带初始提交的临时仓库并切换进去
+func setupGuardRepo(t *testing.T) string {
+	t.Helper()
+	isolateGit(t)
+	dir := t.TempDir()
+	runGitIn(t, dir, "init", "-q", "-b", "main")
+	commitFile(t, dir, "README.md", "初始内容\n")
+	t.Chdir(dir)
+	return dir
+}
+
+// 在 dir 中写入文件但不提交
+func writeWorkFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 以文件作为标准输入提供确认的回答。与 strings.Reader 不同，
+// 中间步骤的git进程直接继承文件，不会读走后面的回答
+func answerFile(t *testing.T, answers string) *os.File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "answers")
+	if err := os.WriteFile(path, []byte(answers), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+// 检查识别结果中包含的内容
+func assertDetails(t *testing.T, op *destructiveOp, want ...string) {
+	t.Helper()
+	if op == nil {
+		t.Fatal("应该识别为破坏性操作")
+	}
+	joined := strings.Join(op.Details, "\n")
+	for _, w := range want {
+		if !strings.Contains(joined, w) {
+			t.Errorf("详情中缺少 %q，实际:\n%s", w, joined)
+		}
+	}
+}
+
+func TestDetectDestructive_ResetHard(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "README.md", "未提交的修改\n")
+
+	assertDetails(t, e.detectDestructive([]string{"reset", "--hard"}), "未提交的修改: M README.md")
+}
+
+func TestDetectDestructive_ResetHardToCommit(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	commitFile(t, dir, "second.txt", "第二个提交\n")
+
+	assertDetails(t, e.detectDestructive([]string{"reset", "--hard", "HEAD~1"}), "将离开当前分支的提交:", "更新 second.txt")
+}
+
+func TestDetectDestructive_CheckoutPaths(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "README.md", "未提交的修改\n")
+
+	assertDetails(t, e.detectDestructive([]string{"checkout", "--", "."}), "将丢弃修改: README.md")
+	assertDetails(t, e.detectDestructive([]string{"checkout", "."}), "将丢弃修改: README.md")
+}
+
+func TestDetectDestructive_Clean(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "untracked.txt", "未跟踪\n")
+
+	assertDetails(t, e.detectDestructive([]string{"clean", "-fd"}), "将删除: untracked.txt")
+	if _, err := os.Stat(filepath.Join(dir, "untracked.txt")); err != nil {
+		t.Error("识别时不应该删除文件")
+	}
+}
+
+func TestDetectDestructive_BranchDelete(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	runGitIn(t, dir, "checkout", "-q", "-b", "feature")
+	commitFile(t, dir, "feature.txt", "功能\n")
+	runGitIn(t, dir, "checkout", "-q", "main")
+
+	assertDetails(t, e.detectDestructive([]string{"branch", "-D", "feature"}), "只在分支 feature 上的提交:", "更新 feature.txt")
+
+	// 提交还在其他分支上时，删除分支不会丢失任何提交
+	runGitIn(t, dir, "branch", "backup", "feature")
+	if op := e.detectDestructive([]string{"branch", "-D", "feature"}); op != nil {
+		t.Errorf("提交还被其他分支引用时不需要确认，得到 %v", op.Details)
+	}
+}
+
+func TestDetectDestructive_ForcePushAlwaysConfirms(t *testing.T) {
+	e := newTestEngine(t)
+	setupGuardRepo(t)
+
+	for _, args := range [][]string{
+		{"push", "-f"},
+		{"push", "--force", "origin", "main"},
+		{"push", "origin", "+main"},
+		{"push", "--force-with-lease"},
+		{"push", "--force-with-lease=main:abc123", "origin", "main"},
+		{"push", "--force-if-includes", "--force-with-lease"},
+		{"push", "--mirror", "backup"},
+	} {
+		if op := e.detectDestructive(args); op == nil {
+			t.Errorf("%v 应该需要确认", args)
+		}
+	}
+}
+
+func TestDetectDestructive_Safe(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "README.md", "未提交的修改\n")
+
+	safe := [][]string{
+		{"reset", "--soft", "HEAD"},
+		{"checkout", "-b", "feature"},
+		{"push"},
+		{"clean", "-n"},
+		{"branch", "-d", "feature"},
+		{"status"},
+	}
+	for _, args := range safe {
+		if op := e.detectDestructive(args); op != nil {
+			t.Errorf("%v 不应该需要确认，得到 %+v", args, op)
+		}
+	}
+
+	// 工作区干净时 reset --hard 不会丢失任何内容
+	runGitIn(t, dir, "checkout", "--", "README.md")
+	if op := e.detectDestructive([]string{"reset", "--hard"}); op != nil {
+		t.Errorf("工作区干净时不应该需要确认，得到 %+v", op)
+	}
+}
+
+func TestConfirmDestructive(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "README.md", "未提交的修改\n")
+	args := []string{"reset", "--hard"}
+
+	e.Stdin = strings.NewReader("n\n")
+	var err error
+	output := captureOutput(e, func() { err = e.confirmDestructive(args) })
+	if !errors.Is(err, ErrAborted) {
+		t.Errorf("拒绝时应该返回 ErrAborted，得到 %v", err)
+	}
+	for _, element := range []string{"⚠️  危险操作: git reset --hard", "未提交的修改: M README.md", "确定要继续吗？[y/N]", "已取消"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+
+	e.Stdin = strings.NewReader("y\n")
+	captureOutput(e, func() { err = e.confirmDestructive(args) })
+	if err != nil {
+		t.Errorf("确认后应该继续执行，得到 %v", err)
+	}
+}
+
+func TestConfirmDestructive_PipedAnswers(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "README.md", "未提交的修改\n")
+	args := []string{"reset", "--hard"}
+
+	// 通过管道一次性输入多行回答，每次确认读取其中一行
+	e.Stdin = strings.NewReader("n\ny\n")
+	var first, second error
+	captureOutput(e, func() {
+		first = e.confirmDestructive(args)
+		second = e.confirmDestructive(args)
+	})
+	if !errors.Is(first, ErrAborted) {
+		t.Errorf("第一次确认应该读到 n，得到 %v", first)
+	}
+	if second != nil {
+		t.Errorf("第二次确认应该读到 y，得到 %v", second)
+	}
+
+	// Run 复制的引擎共用同一个读取器
+	e.Stdin = strings.NewReader("y\ny\n")
+	captureOutput(e, func() { e.confirmDestructive(args) })
+	run := *e
+	if err := run.confirmDestructive(args); err != nil {
+		t.Errorf("复制的引擎应该读到剩余的回答，得到 %v", err)
+	}
+}
+
+func TestConfirmDestructive_Disabled(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "README.md", "未提交的修改\n")
+	args := []string{"reset", "--hard"}
+
+	// 没有可读的输入，如果请求确认会被视为拒绝
+	e.Stdin = strings.NewReader("")
+
+	e.AssumeYes = true
+	err := e.confirmDestructive(args)
+	e.AssumeYes = false
+	if err != nil {
+		t.Errorf("--yes 时不应该请求确认，得到 %v", err)
+	}
+
+	disabled := false
+	e.config.Settings.ConfirmDestructive = &disabled
+	t.Cleanup(func() { e.config.Settings.ConfirmDestructive = nil })
+	if err := e.confirmDestructive(args); err != nil {
+		t.Errorf("配置关闭确认时不应该请求确认，得到 %v", err)
+	}
+}
+
+func TestExecuteGitCommand_Aborted(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "README.md", "未提交的修改\n")
+	e.Stdin = strings.NewReader("n\n")
+
+	var err error
+	captureOutput(e, func() { err = e.Execute("ht", []string{"--hard"}) })
+	if !errors.Is(err, ErrAborted) {
+		t.Errorf("取消时应该返回 ErrAborted，得到 %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+	if string(content) != "未提交的修改\n" {
+		t.Error("取消后不应该执行 git reset --hard")
+	}
+}
+
+func TestParseGlobalFlags_Yes(t *testing.T) {
+	e := newTestEngine(t)
+	args := e.parseGlobalFlags([]string{"-y", "ht", "--hard"})
+	if !e.AssumeYes || len(args) != 2 || args[0] != "ht" {
+		t.Errorf("应该识别 -y，得到 AssumeYes=%v args=%v", e.AssumeYes, args)
+	}
+}
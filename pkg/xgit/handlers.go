@@ -0,0 +1,424 @@
+package xgit
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 内置的复合命令处理器，用于需要根据仓库状态决定下一步的流程。
+// 复合命令通过 handler 字段引用处理器，steps 仍用于帮助和等价命令的展示，
+// git步骤失败时返回 *StepError，步骤编号与 steps 对应
+var compositeHandlers = map[string]func(e *Engine, cmdName string, spec CompositeCommand, args []string) error{
+	"sync-branch":  (*Engine).syncBranch,
+	"setup-remote": (*Engine).setupRemote,
+	"quick-commit": (*Engine).quickCommit,
+	"undo":         (*Engine).undoOperations,
+}
+
+// 同步分支：获取远程更新，切换到目标分支（必要时创建跟踪分支），然后快进拉取
+func (e *Engine) syncBranch(cmdName string, spec CompositeCommand, args []string) error {
+	if len(args) == 0 {
+		return &missingArgError{Index: 1}
+	}
+	if len(args) > 2 {
+		return &extraArgsError{Args: args[2:]}
+	}
+
+	branch := args[0]
+	remote := "origin"
+	if len(args) > 1 {
+		remote = args[1]
+	}
+	remoteRef := remote + "/" + branch
+	total := len(spec.Steps)
+
+	// 工作区有未提交的更改时切换分支可能丢失或混入修改
+	status, err := e.gitOutput("status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return fmt.Errorf("无法读取仓库状态: %v", err)
+	}
+	if status != "" {
+		return fmt.Errorf("工作区有未提交的更改，无法同步分支:\n  %s\n请先提交 (xgit kstj \"提交信息\") 或储藏 (xgit git stash) 后重试",
+			strings.ReplaceAll(status, "\n", "\n  "))
+	}
+
+	// 1. git fetch
+	fmt.Fprintf(e.Stdout, "→ [1/%d] 获取远程更新: %s\n", total, remote)
+	fetchArgs := []string{"fetch", remote}
+	if err := e.executeGitCommandWithError(fetchArgs); err != nil {
+		return newStepError(cmdName, 1, total, fetchArgs, err)
+	}
+
+	// 2. git checkout
+	var checkoutArgs []string
+	created := false
+	if e.refExists("refs/heads/" + branch) {
+		if current, _ := e.currentBranch(); current != branch {
+			fmt.Fprintf(e.Stdout, "→ [2/%d] 切换到分支: %s\n", total, branch)
+			checkoutArgs = []string{"checkout", branch}
+		}
+	} else if e.refExists("refs/remotes/" + remoteRef) {
+		fmt.Fprintf(e.Stdout, "→ [2/%d] 创建跟踪分支: %s → %s\n", total, branch, remoteRef)
+		checkoutArgs = []string{"checkout", "-b", branch, "--track", remoteRef}
+		created = true
+	} else {
+		return fmt.Errorf("本地和远程 %s 中都不存在分支 %s", remote, branch)
+	}
+	if checkoutArgs != nil {
+		if err := e.executeGitCommandWithError(checkoutArgs); err != nil {
+			return newStepError(cmdName, 2, total, checkoutArgs, err)
+		}
+	}
+
+	// 新创建的跟踪分支与远程一致，无需拉取
+	if created {
+		fmt.Fprintf(e.Stdout, "分支 %s 已与 %s 同步\n", branch, remoteRef)
+		return nil
+	}
+	if !e.refExists("refs/remotes/" + remoteRef) {
+		fmt.Fprintf(e.Stdout, "远程分支 %s 不存在，跳过拉取\n", remoteRef)
+		return nil
+	}
+
+	// 3. 只允许快进，分叉时交给用户决定合并还是变基
+	ahead, behind, err := e.aheadBehind(branch, remoteRef)
+	if err != nil {
+		return fmt.Errorf("无法比较 %s 与 %s: %v", branch, remoteRef, err)
+	}
+	switch {
+	case ahead > 0 && behind > 0:
+		return fmt.Errorf("本地分支 %s 与 %s 已分叉（本地领先 %d 个提交，落后 %d 个提交）\n请使用 xgit hb %s 合并或 xgit zf %s 变基后再同步",
+			branch, remoteRef, ahead, behind, remoteRef, remoteRef)
+	case behind == 0:
+		if ahead > 0 {
+			fmt.Fprintf(e.Stdout, "分支 %s 已包含远程的所有提交，本地领先 %d 个提交，可使用 xgit ts 推送\n", branch, ahead)
+		} else {
+			fmt.Fprintf(e.Stdout, "分支 %s 已是最新\n", branch)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(e.Stdout, "→ [3/%d] 快进拉取 %d 个提交\n", total, behind)
+	pullArgs := []string{"pull", "--ff-only", remote, branch}
+	if err := e.executeGitCommandWithError(pullArgs); err != nil {
+		return newStepError(cmdName, 3, total, pullArgs, err)
+	}
+
+	fmt.Fprintf(e.Stdout, "分支 %s 已与 %s 同步\n", branch, remoteRef)
+	return nil
+}
+
+// 统计 local 相对 upstream 领先和落后的提交数
+func (e *Engine) aheadBehind(local, upstream string) (ahead, behind int, err error) {
+	out, err := e.gitOutput("rev-list", "--left-right", "--count", local+"..."+upstream)
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("无法解析 git rev-list 输出: %s", out)
+	}
+	if ahead, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, err
+	}
+	if behind, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// scp 风格的远程地址 [user@]host:path，如 git@github.com:user/repo.git、
+// gh:user/repo.git（ssh配置中的主机别名）、myserver:/srv/git/repo.git
+var scpLikeURL = regexp.MustCompile(`^(?:[\w.+-]+@)?([\w.-]+):([^\\]+)$`)
+
+// 是否为 scp 风格的地址。与git的判断一致：单个字母的主机名是 Windows 盘符（如 C:\repo），
+// host:// 开头的是协议地址
+func isSCPLikeURL(remoteURL string) bool {
+	m := scpLikeURL.FindStringSubmatch(remoteURL)
+	return m != nil && len(m[1]) > 1 && !strings.HasPrefix(m[2], "//")
+}
+
+// 检查远程仓库地址的格式：支持的协议地址、scp 风格地址或本地已存在的目录。
+// 相对路径和 git remote add 一样相对于 e.Dir
+func (e *Engine) validRemoteURL(remoteURL string) bool {
+	if isSCPLikeURL(remoteURL) {
+		return true
+	}
+	if u, err := url.Parse(remoteURL); err == nil && u.Scheme != "" {
+		switch u.Scheme {
+		case "http", "https", "ssh", "git", "ftp", "ftps":
+			return u.Host != ""
+		case "file":
+			return u.Path != ""
+		}
+		return false
+	}
+	path := remoteURL
+	if e.Dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(e.Dir, path)
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// 检查远程仓库能否访问，禁止git在终端中询问用户名密码
+func (e *Engine) checkRemoteReachable(remoteURL string) error {
+	result, err := e.Runner.Run(GitCommand{
+		Args: []string{"ls-remote", "-q", remoteURL},
+		Dir:  e.Dir,
+		Env:  []string{"GIT_TERMINAL_PROMPT=0"},
+	})
+	if err != nil {
+		return fmt.Errorf("无法访问远程仓库 %s:\n  %s\n请检查地址和访问权限，或使用 --no-check 跳过检查",
+			remoteURL, strings.ReplaceAll(strings.TrimSpace(result.Stderr), "\n", "\n  "))
+	}
+	return nil
+}
+
+// 设置远程仓库：添加（或更新已存在的）远程仓库，然后推送当前分支并设置上游。
+// 支持 --name <远程仓库名> 和 --no-check（跳过地址格式和可访问性检查）
+func (e *Engine) setupRemote(cmdName string, spec CompositeCommand, args []string) error {
+	remote := "origin"
+	check := true
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--name":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--name 需要提供远程仓库名")
+			}
+			i++
+			remote = args[i]
+		case strings.HasPrefix(arg, "--name="):
+			remote = strings.TrimPrefix(arg, "--name=")
+		case arg == "--no-check":
+			check = false
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) == 0 {
+		return &missingArgError{Index: 1}
+	}
+	if len(positional) > 2 {
+		return &extraArgsError{Args: positional[2:]}
+	}
+	if remote == "" {
+		return fmt.Errorf("远程仓库名不能为空")
+	}
+
+	remoteURL := positional[0]
+	if check && !e.validRemoteURL(remoteURL) {
+		return fmt.Errorf("无效的远程仓库URL: %s\n支持 https://、ssh://、[user@]host:path 形式的地址或本地仓库目录，或使用 --no-check 跳过检查", remoteURL)
+	}
+
+	// 未指定分支时推送当前分支，尚无提交的分支也能识别
+	var branch string
+	unborn := false
+	if len(positional) > 1 {
+		branch = positional[1]
+	} else {
+		current, err := e.currentBranch()
+		if err != nil {
+			return fmt.Errorf("%v\n请指定要推送的分支: %s", err, compositeUsage(cmdName, spec))
+		}
+		branch = current
+		unborn = !e.refExists("refs/heads/" + branch)
+	}
+
+	if check {
+		fmt.Fprintf(e.Stdout, "检查远程仓库: %s\n", remoteURL)
+		if err := e.checkRemoteReachable(remoteURL); err != nil {
+			return err
+		}
+	}
+	total := len(spec.Steps)
+
+	// 1. 添加远程仓库，已存在时更新地址
+	var remoteArgs []string
+	if existing, err := e.gitOutput("remote", "get-url", remote); err != nil {
+		fmt.Fprintf(e.Stdout, "→ [1/%d] 添加远程仓库: %s → %s\n", total, remote, remoteURL)
+		remoteArgs = []string{"remote", "add", remote, remoteURL}
+	} else if existing != remoteURL {
+		fmt.Fprintf(e.Stdout, "→ [1/%d] 更新远程仓库: %s 从 %s 改为 %s\n", total, remote, existing, remoteURL)
+		remoteArgs = []string{"remote", "set-url", remote, remoteURL}
+	} else {
+		fmt.Fprintf(e.Stdout, "远程仓库 %s 已指向 %s\n", remote, remoteURL)
+	}
+	if remoteArgs != nil {
+		if err := e.executeGitCommandWithError(remoteArgs); err != nil {
+			return newStepError(cmdName, 1, total, remoteArgs, err)
+		}
+	}
+
+	// 2. 推送并设置上游，分支还没有提交时无法推送
+	if unborn {
+		fmt.Fprintf(e.Stdout, "分支 %s 还没有提交，跳过推送\n提交后运行 xgit git push -u %s %s 推送\n", branch, remote, branch)
+		return nil
+	}
+	fmt.Fprintf(e.Stdout, "→ [2/%d] 推送分支: %s → %s\n", total, branch, remote)
+	pushArgs := []string{"push", "-u", remote, branch}
+	if err := e.executeGitCommandWithError(pushArgs); err != nil {
+		return newStepError(cmdName, 2, total, pushArgs, err)
+	}
+	return nil
+}
+
+// 快速提交：暂存、提交并推送。
+//
+//	xgit kstj "提交信息" [路径...]   只暂存指定路径，未指定时暂存当前目录
+//	-a, --all                       只暂存已跟踪文件的修改（git add -u）
+//	--amend                         修改上一次提交，省略提交信息时沿用原信息
+//	--no-push                       只提交，不推送
+//	--                              之后的参数都是提交信息和路径，可以以 - 开头
+func (e *Engine) quickCommit(cmdName string, spec CompositeCommand, args []string) error {
+	all, amend, push := false, false, true
+	var positional []string
+	for i, arg := range args {
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		switch arg {
+		case "-a", "--all":
+			all = true
+		case "--amend":
+			amend = true
+		case "--no-push":
+			push = false
+		case "-m", "--message":
+			return fmt.Errorf("未知选项: %s，提交信息直接写在命令后面\n用法: %s", arg, compositeUsage(cmdName, spec))
+		default:
+			// 以 - 开头的提交信息或路径需要放在 -- 之后
+			if len(arg) > 1 && strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("未知选项: %s\n用法: %s", arg, compositeUsage(cmdName, spec))
+			}
+			positional = append(positional, arg)
+		}
+	}
+
+	var message string
+	var paths []string
+	switch {
+	case len(positional) > 0:
+		message, paths = positional[0], positional[1:]
+	case !amend:
+		return &missingArgError{Index: 1}
+	}
+	if all && len(paths) > 0 {
+		return fmt.Errorf("-a 不能与路径同时使用")
+	}
+	total := len(spec.Steps)
+
+	// 修改已推送的提交后需要强制推送，先记录修改前是否已推送
+	amendPushed := false
+	if amend && push {
+		_, err := e.gitOutput("merge-base", "--is-ancestor", "HEAD", "@{upstream}")
+		amendPushed = err == nil
+	}
+
+	// 1. git add
+	addArgs := []string{"add", "."}
+	switch {
+	case all:
+		addArgs = []string{"add", "-u"}
+	case len(paths) > 0:
+		addArgs = append([]string{"add", "--"}, paths...)
+	}
+	if !e.DryRun {
+		fmt.Fprintf(e.Stdout, "→ [1/%d] %s\n", total, formatGitCommand(addArgs))
+	}
+	if err := e.executeGitCommandWithError(addArgs); err != nil {
+		return newStepError(cmdName, 1, total, addArgs, err)
+	}
+
+	// 没有暂存任何修改时停止，而不是让 git commit 失败
+	if !e.DryRun && !amend {
+		if _, err := e.gitOutput("diff", "--cached", "--quiet"); err == nil {
+			fmt.Fprintln(e.Stdout, "没有需要提交的更改，工作区是干净的")
+			return nil
+		}
+	}
+
+	// 2. git commit
+	commitArgs := []string{"commit", "-m", message}
+	if amend {
+		commitArgs = []string{"commit", "--amend", "-m", message}
+		if message == "" {
+			commitArgs = []string{"commit", "--amend", "--no-edit"}
+		}
+	}
+	if !e.DryRun {
+		fmt.Fprintf(e.Stdout, "→ [2/%d] %s\n", total, formatGitCommand(commitArgs))
+	}
+	if err := e.executeGitCommandWithError(commitArgs); err != nil {
+		return newStepError(cmdName, 2, total, commitArgs, err)
+	}
+
+	if !push {
+		return nil
+	}
+
+	// 3. git push，没有上游分支时推送到默认远程仓库并设置上游
+	pushArgs := []string{"push"}
+	if amendPushed {
+		// 强制推送和其他破坏性操作一样需要确认（-y 跳过）
+		fmt.Fprintln(e.Stdout, "修改的提交已经推送过，需要使用 --force-with-lease 强制推送")
+		pushArgs = append(pushArgs, "--force-with-lease")
+	}
+	if _, err := e.gitOutput("rev-parse", "--abbrev-ref", "@{upstream}"); err != nil {
+		// 提交已经完成，但没有推送的 kstj 不能当作成功，脚本要能从退出码发现
+		branch, err := e.currentBranch()
+		if err != nil {
+			return fmt.Errorf("提交后无法推送: %v\n只提交不推送请使用 --no-push", err)
+		}
+		remote, err := e.defaultRemote()
+		if err != nil {
+			return fmt.Errorf("提交后无法推送: %v\n只提交不推送请使用 --no-push", err)
+		}
+		fmt.Fprintf(e.Stdout, "分支 %s 没有上游分支，推送到 %s 并设置上游\n", branch, remote)
+		pushArgs = append(pushArgs, "-u", remote, branch)
+	}
+	if !e.DryRun {
+		fmt.Fprintf(e.Stdout, "→ [3/%d] %s\n", total, formatGitCommand(pushArgs))
+	}
+	if err := e.executeGitCommandWithError(pushArgs); err != nil {
+		if errors.Is(err, ErrAborted) {
+			fmt.Fprintln(e.Stdout, "没有推送，修改后的提交只在本地")
+			return err
+		}
+		return newStepError(cmdName, 3, total, pushArgs, err)
+	}
+	return nil
+}
+
+// 没有上游分支时推送的远程仓库：优先 remote.pushDefault，其次 origin，
+// 只有一个远程仓库时使用它
+func (e *Engine) defaultRemote() (string, error) {
+	if remote, err := e.gitOutput("config", "remote.pushDefault"); err == nil && remote != "" {
+		return remote, nil
+	}
+	out, err := e.gitOutput("remote")
+	if err != nil {
+		return "", fmt.Errorf("无法读取远程仓库: %v", err)
+	}
+	remotes := outputLines(out)
+	switch {
+	case len(remotes) == 0:
+		return "", fmt.Errorf("没有配置远程仓库（可使用 xgit ycsh <远程仓库URL> 设置）")
+	case len(remotes) == 1:
+		return remotes[0], nil
+	}
+	for _, remote := range remotes {
+		if remote == "origin" {
+			return remote, nil
+		}
+	}
+	return "", fmt.Errorf("有多个远程仓库 (%s)，请先设置上游分支或 remote.pushDefault", strings.Join(remotes, ", "))
+}
@@ -0,0 +1,584 @@
+package xgit
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 在 dir 中执行git命令，失败时终止测试
+func runGitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s 失败: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// 在 dir 中创建文件并提交
+func commitFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitIn(t, dir, "add", name)
+	runGitIn(t, dir, "commit", "-q", "-m", "更新 "+name)
+}
+
+// 隔离的git环境：使用临时的HOME，避免读取开发者的全局配置
+func isolateGit(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "xgit")
+	t.Setenv("GIT_AUTHOR_EMAIL", "xgit@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "xgit")
+	t.Setenv("GIT_COMMITTER_EMAIL", "xgit@example.com")
+}
+
+// 创建远程仓库、用于模拟他人推送的 seed 克隆和当前工作克隆，并切换到工作克隆
+func setupSyncRepos(t *testing.T) (seed, work string) {
+	t.Helper()
+	isolateGit(t)
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	seed = filepath.Join(root, "seed")
+	work = filepath.Join(root, "work")
+
+	runGitIn(t, root, "init", "-q", "--bare", "-b", "main", remote)
+	runGitIn(t, root, "clone", "-q", remote, seed)
+	runGitIn(t, seed, "checkout", "-q", "-b", "main")
+	commitFile(t, seed, "README.md", "初始内容\n")
+	runGitIn(t, seed, "push", "-q", "-u", "origin", "main")
+	runGitIn(t, seed, "checkout", "-q", "-b", "feature")
+	commitFile(t, seed, "feature.txt", "功能\n")
+	runGitIn(t, seed, "push", "-q", "-u", "origin", "feature")
+	runGitIn(t, seed, "checkout", "-q", "main")
+
+	runGitIn(t, root, "clone", "-q", remote, work)
+	t.Chdir(work)
+	return seed, work
+}
+
+func TestSyncBranch_CreatesTrackingBranch(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupSyncRepos(t)
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("tbfz", e.compositeCommands["tbfz"], []string{"feature"})
+	})
+
+	if err != nil {
+		t.Fatalf("同步分支失败: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "创建跟踪分支: feature → origin/feature") {
+		t.Errorf("应该创建跟踪分支，实际输出:\n%s", output)
+	}
+	if branch := runGitIn(t, work, "symbolic-ref", "--short", "HEAD"); branch != "feature" {
+		t.Errorf("应该切换到 feature 分支，当前分支 %s", branch)
+	}
+	if upstream := runGitIn(t, work, "rev-parse", "--abbrev-ref", "@{u}"); upstream != "origin/feature" {
+		t.Errorf("feature 应该跟踪 origin/feature，得到 %s", upstream)
+	}
+}
+
+func TestSyncBranch_FastForward(t *testing.T) {
+	e := newTestEngine(t)
+	seed, work := setupSyncRepos(t)
+	commitFile(t, seed, "README.md", "远程更新\n")
+	runGitIn(t, seed, "push", "-q")
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("tbfz", e.compositeCommands["tbfz"], []string{"main"})
+	})
+
+	if err != nil {
+		t.Fatalf("同步分支失败: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "快进拉取 1 个提交") || !strings.Contains(output, "分支 main 已与 origin/main 同步") {
+		t.Errorf("应该快进拉取远程提交，实际输出:\n%s", output)
+	}
+	if runGitIn(t, work, "rev-parse", "HEAD") != runGitIn(t, seed, "rev-parse", "HEAD") {
+		t.Error("同步后本地 main 应该与远程一致")
+	}
+}
+
+func TestSyncBranch_UpToDate(t *testing.T) {
+	e := newTestEngine(t)
+	setupSyncRepos(t)
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("tbfz", e.compositeCommands["tbfz"], []string{"main"})
+	})
+
+	if err != nil {
+		t.Fatalf("同步分支失败: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "分支 main 已是最新") {
+		t.Errorf("没有远程更新时应该提示已是最新，实际输出:\n%s", output)
+	}
+}
+
+func TestSyncBranch_Diverged(t *testing.T) {
+	e := newTestEngine(t)
+	seed, work := setupSyncRepos(t)
+	commitFile(t, seed, "README.md", "远程更新\n")
+	runGitIn(t, seed, "push", "-q")
+	commitFile(t, work, "local.txt", "本地提交\n")
+	before := runGitIn(t, work, "rev-parse", "HEAD")
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("tbfz", e.compositeCommands["tbfz"], []string{"main"})
+	})
+
+	if err == nil || ExitCode(err) != 1 {
+		t.Errorf("分叉时应该返回错误，得到 %v", err)
+	}
+	if !strings.Contains(output, "已分叉（本地领先 1 个提交，落后 1 个提交）") {
+		t.Errorf("分叉时应该报告领先和落后的提交数，实际输出:\n%s", output)
+	}
+	if after := runGitIn(t, work, "rev-parse", "HEAD"); after != before {
+		t.Error("分叉时不应该修改本地分支")
+	}
+}
+
+func TestSyncBranch_DirtyWorktree(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupSyncRepos(t)
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("未提交的修改\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("tbfz", e.compositeCommands["tbfz"], []string{"feature"})
+	})
+
+	if err == nil {
+		t.Error("工作区不干净时应该返回错误")
+	}
+	if !strings.Contains(output, "工作区有未提交的更改") || !strings.Contains(output, "README.md") {
+		t.Errorf("工作区不干净时应该列出修改的文件并停止，实际输出:\n%s", output)
+	}
+	if strings.Contains(output, "→") {
+		t.Errorf("工作区不干净时不应执行任何步骤，实际输出:\n%s", output)
+	}
+}
+
+func TestSyncBranch_MissingBranch(t *testing.T) {
+	e := newTestEngine(t)
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("tbfz", e.compositeCommands["tbfz"], []string{})
+	})
+
+	if err == nil {
+		t.Error("缺少分支名时应该返回错误")
+	}
+
+	for _, element := range []string{"错误: 需要提供分支名", "用法: xgit tbfz <分支名> [远程仓库名]"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+}
+
+// 创建远程裸仓库和一个使用 master 分支的本地仓库，并切换到本地仓库
+func setupRemoteRepos(t *testing.T, withCommit bool) (remote, work string) {
+	t.Helper()
+	isolateGit(t)
+
+	root := t.TempDir()
+	remote = filepath.Join(root, "remote.git")
+	work = filepath.Join(root, "work")
+	runGitIn(t, root, "init", "-q", "--bare", remote)
+	runGitIn(t, root, "init", "-q", "-b", "master", work)
+	if withCommit {
+		commitFile(t, work, "README.md", "初始内容\n")
+	}
+	t.Chdir(work)
+	return remote, work
+}
+
+func TestSetupRemote_PushesCurrentBranch(t *testing.T) {
+	e := newTestEngine(t)
+	remote, work := setupRemoteRepos(t, true)
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("ycsh", e.compositeCommands["ycsh"], []string{remote})
+	})
+
+	if err != nil {
+		t.Fatalf("设置远程仓库失败: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "推送分支: master → origin") {
+		t.Errorf("应该推送当前分支 master，实际输出:\n%s", output)
+	}
+	if upstream := runGitIn(t, work, "rev-parse", "--abbrev-ref", "@{u}"); upstream != "origin/master" {
+		t.Errorf("master 应该跟踪 origin/master，得到 %s", upstream)
+	}
+}
+
+func TestSetupRemote_UnbornBranch(t *testing.T) {
+	e := newTestEngine(t)
+	remote, work := setupRemoteRepos(t, false)
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("ycsh", e.compositeCommands["ycsh"], []string{remote})
+	})
+
+	if err != nil {
+		t.Fatalf("设置远程仓库失败: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "分支 master 还没有提交，跳过推送") {
+		t.Errorf("没有提交时应该跳过推送，实际输出:\n%s", output)
+	}
+	if url := runGitIn(t, work, "remote", "get-url", "origin"); url != remote {
+		t.Errorf("应该添加 origin，得到 %s", url)
+	}
+}
+
+func TestSetupRemote_UpdatesExistingRemote(t *testing.T) {
+	e := newTestEngine(t)
+	remote, work := setupRemoteRepos(t, true)
+	runGitIn(t, work, "remote", "add", "origin", "https://example.com/old.git")
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("ycsh", e.compositeCommands["ycsh"], []string{remote})
+	})
+
+	if err != nil {
+		t.Fatalf("设置远程仓库失败: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "更新远程仓库: origin 从 https://example.com/old.git 改为 "+remote) {
+		t.Errorf("已存在的远程仓库应该被更新，实际输出:\n%s", output)
+	}
+	if url := runGitIn(t, work, "remote", "get-url", "origin"); url != remote {
+		t.Errorf("origin 应该指向 %s，得到 %s", remote, url)
+	}
+}
+
+func TestSetupRemote_Name(t *testing.T) {
+	e := newTestEngine(t)
+	remote, work := setupRemoteRepos(t, true)
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("ycsh", e.compositeCommands["ycsh"], []string{"--name", "upstream", remote, "master"})
+	})
+
+	if err != nil {
+		t.Fatalf("设置远程仓库失败: %v\n%s", err, output)
+	}
+	if upstream := runGitIn(t, work, "rev-parse", "--abbrev-ref", "@{u}"); upstream != "upstream/master" {
+		t.Errorf("master 应该跟踪 upstream/master，得到 %s", upstream)
+	}
+}
+
+func TestSetupRemote_InvalidRemote(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupRemoteRepos(t, true)
+	notRepo := t.TempDir()
+
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"格式错误", "not a url", "无效的远程仓库URL: not a url"},
+		{"无法访问", notRepo, "无法访问远程仓库 " + notRepo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			output := captureOutput(e, func() {
+				err = e.executeCompositeCommand("ycsh", e.compositeCommands["ycsh"], []string{tt.url})
+			})
+
+			if err == nil || !strings.Contains(output, tt.expected) {
+				t.Errorf("应该报告 %q，得到 %v\n实际输出:\n%s", tt.expected, err, output)
+			}
+			if strings.Contains(output, "→") {
+				t.Errorf("地址无效时不应执行任何步骤，实际输出:\n%s", output)
+			}
+			if remotes := runGitIn(t, work, "remote"); remotes != "" {
+				t.Errorf("地址无效时不应该添加远程仓库，得到 %s", remotes)
+			}
+		})
+	}
+}
+
+func TestSetupRemote_NoCheckSkipsFormatCheck(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupRemoteRepos(t, true)
+	e.DryRun = true
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("ycsh", e.compositeCommands["ycsh"], []string{"--no-check", "my alias:repo"})
+	})
+	if err != nil {
+		t.Fatalf("--no-check 时不应该检查地址格式: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "git remote add origin 'my alias:repo'") || strings.Contains(output, "检查远程仓库") {
+		t.Errorf("应该直接添加远程仓库，实际输出:\n%s", output)
+	}
+	if remotes := runGitIn(t, work, "remote"); remotes != "" {
+		t.Errorf("预演模式不应该添加远程仓库，得到 %s", remotes)
+	}
+}
+
+func TestValidRemoteURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://github.com/user/repo.git", true},
+		{"ssh://git@github.com/user/repo.git", true},
+		{"git@github.com:user/repo.git", true},
+		{"gh:user/repo.git", true},
+		{"github.com:user/repo.git", true},
+		{"myserver:/srv/git/x.git", true},
+		{"deploy@my-server:repo.git", true},
+		{"file:///srv/repo.git", true},
+		{t.TempDir(), true},
+		{"https://", false},
+		{"github.com/user/repo", false},
+		{"ftp2://host/repo", false},
+		{`C:\repo`, false},
+		{"C:/repo", false},
+	}
+
+	e := newTestEngine(t)
+	for _, tt := range tests {
+		if got := e.validRemoteURL(tt.url); got != tt.valid {
+			t.Errorf("validRemoteURL(%q) = %v，期望 %v", tt.url, got, tt.valid)
+		}
+	}
+
+	// 相对路径相对于 Engine.Dir，而不是进程的当前目录
+	t.Chdir(t.TempDir())
+	e.Dir = t.TempDir()
+	if err := os.Mkdir(filepath.Join(e.Dir, "upstream"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if !e.validRemoteURL("upstream") || !e.validRemoteURL("../"+filepath.Base(e.Dir)+"/upstream") {
+		t.Error("Engine.Dir 下的相对路径应该有效")
+	}
+	e.Dir = t.TempDir()
+	if e.validRemoteURL("upstream") {
+		t.Error("Engine.Dir 下不存在的相对路径应该无效")
+	}
+}
+
+// 执行快速提交，失败时终止测试
+func runQuickCommit(t *testing.T, e *Engine, args ...string) string {
+	t.Helper()
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("kstj", e.compositeCommands["kstj"], args)
+	})
+	if err != nil {
+		t.Fatalf("快速提交失败: %v\n%s", err, output)
+	}
+	return output
+}
+
+func TestQuickCommit_NothingToCommit(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupSyncRepos(t)
+	before := runGitIn(t, work, "rev-parse", "HEAD")
+
+	output := runQuickCommit(t, e, "提交信息")
+
+	if !strings.Contains(output, "没有需要提交的更改") {
+		t.Errorf("没有更改时应该提示并停止，实际输出:\n%s", output)
+	}
+	if strings.Contains(output, "git commit") {
+		t.Errorf("没有更改时不应该执行提交，实际输出:\n%s", output)
+	}
+	if after := runGitIn(t, work, "rev-parse", "HEAD"); after != before {
+		t.Error("没有更改时不应该产生新提交")
+	}
+}
+
+func TestQuickCommit_Pathspecs(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupSyncRepos(t)
+	writeWorkFile(t, work, "a.txt", "a\n")
+	writeWorkFile(t, work, "b.txt", "b\n")
+
+	runQuickCommit(t, e, "只提交 a", "a.txt", "--no-push")
+
+	if files := runGitIn(t, work, "show", "--name-only", "--format=", "HEAD"); files != "a.txt" {
+		t.Errorf("应该只提交 a.txt，得到 %s", files)
+	}
+	if status := runGitIn(t, work, "status", "--porcelain"); status != "?? b.txt" {
+		t.Errorf("b.txt 应该保持未跟踪，得到 %s", status)
+	}
+	if ahead := runGitIn(t, work, "rev-list", "--count", "@{u}..HEAD"); ahead != "1" {
+		t.Errorf("--no-push 时不应该推送，本地领先 %s 个提交", ahead)
+	}
+}
+
+func TestQuickCommit_AllTracked(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupSyncRepos(t)
+	writeWorkFile(t, work, "README.md", "修改\n")
+	writeWorkFile(t, work, "new.txt", "新文件\n")
+
+	runQuickCommit(t, e, "-a", "更新 README", "--no-push")
+
+	if files := runGitIn(t, work, "show", "--name-only", "--format=", "HEAD"); files != "README.md" {
+		t.Errorf("-a 应该只提交已跟踪文件的修改，得到 %s", files)
+	}
+}
+
+func TestQuickCommit_NoRemote(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "a.txt", "a\n")
+
+	// 提交了却没有推送时退出码不能为 0
+	var code int
+	output := captureOutput(e, func() { code = e.Run([]string{"kstj", "添加 a"}) })
+	if code == 0 || strings.Contains(output, "完成") {
+		t.Errorf("没有远程仓库时不应该报告成功，退出码 %d\n%s", code, output)
+	}
+	for _, element := range []string{"提交后无法推送: 没有配置远程仓库", "只提交不推送请使用 --no-push"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+	if message := runGitIn(t, dir, "log", "-1", "--format=%s"); message != "添加 a" {
+		t.Errorf("推送失败前应该已经提交，最新提交为 %s", message)
+	}
+
+	// 分离头指针时同样失败，--no-push 时成功
+	runGitIn(t, dir, "checkout", "-q", "--detach")
+	writeWorkFile(t, dir, "b.txt", "b\n")
+	if err := e.executeCompositeCommand("kstj", e.compositeCommands["kstj"], []string{"添加 b"}); err == nil || !strings.Contains(err.Error(), "分离头指针") {
+		t.Errorf("分离头指针时应该返回错误，得到 %v", err)
+	}
+	writeWorkFile(t, dir, "c.txt", "c\n")
+	runQuickCommit(t, e, "添加 c", "--no-push")
+}
+
+func TestQuickCommit_AmendPushedDeclined(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupSyncRepos(t)
+	remoteHead := runGitIn(t, work, "rev-parse", "origin/main")
+	writeWorkFile(t, work, "README.md", "补充\n")
+	e.Stdin = answerFile(t, "n\n")
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("kstj", e.compositeCommands["kstj"], []string{"--amend"})
+	})
+	if !errors.Is(err, ErrAborted) {
+		t.Fatalf("拒绝强制推送时应该返回 ErrAborted，得到 %v\n%s", err, output)
+	}
+	for _, element := range []string{"⚠️  危险操作: git push --force-with-lease", "没有推送，修改后的提交只在本地"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+	if got := runGitIn(t, work, "rev-parse", "origin/main"); got != remoteHead {
+		t.Error("拒绝后不应该强制推送")
+	}
+	if runGitIn(t, work, "rev-parse", "HEAD") == remoteHead {
+		t.Error("提交应该已经在本地修改")
+	}
+}
+
+func TestQuickCommit_UnknownOptions(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupSyncRepos(t)
+	writeWorkFile(t, work, "a.txt", "a\n")
+	before := runGitIn(t, work, "rev-parse", "HEAD")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-m", "提交信息"}, "未知选项: -m，提交信息直接写在命令后面"},
+		{[]string{"提交信息", "--no-pus"}, "未知选项: --no-pus"},
+		{[]string{"-x", "提交信息"}, "未知选项: -x"},
+	}
+	for _, tt := range tests {
+		var err error
+		output := captureOutput(e, func() {
+			err = e.executeCompositeCommand("kstj", e.compositeCommands["kstj"], tt.args)
+		})
+		if err == nil || !strings.Contains(output, tt.expected) || !strings.Contains(output, "用法: xgit kstj") {
+			t.Errorf("kstj %v 应该报告 %q，得到 %v\n实际输出:\n%s", tt.args, tt.expected, err, output)
+		}
+		if strings.Contains(output, "→") {
+			t.Errorf("选项错误时不应执行任何步骤，实际输出:\n%s", output)
+		}
+	}
+	if status := runGitIn(t, work, "status", "--porcelain"); status != "?? a.txt" {
+		t.Errorf("选项错误时不应该暂存文件，得到 %s", status)
+	}
+	if after := runGitIn(t, work, "rev-parse", "HEAD"); after != before {
+		t.Error("选项错误时不应该产生新提交")
+	}
+
+	runQuickCommit(t, e, "--no-push", "--", "-开头的提交信息", "a.txt")
+	if got := runGitIn(t, work, "log", "-1", "--format=%s"); got != "-开头的提交信息" {
+		t.Errorf("-- 之后的参数应该作为提交信息，得到 %q", got)
+	}
+}
+
+func TestQuickCommit_SetsUpstream(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupSyncRepos(t)
+	runGitIn(t, work, "checkout", "-q", "-b", "topic")
+	writeWorkFile(t, work, "topic.txt", "新功能\n")
+
+	output := runQuickCommit(t, e, "新功能")
+
+	if !strings.Contains(output, "分支 topic 没有上游分支，推送到 origin 并设置上游") || !strings.Contains(output, "git push -u origin topic") {
+		t.Errorf("没有上游分支时应该使用 push -u，实际输出:\n%s", output)
+	}
+	if upstream := runGitIn(t, work, "rev-parse", "--abbrev-ref", "@{u}"); upstream != "origin/topic" {
+		t.Errorf("topic 应该跟踪 origin/topic，得到 %s", upstream)
+	}
+}
+
+func TestQuickCommit_AmendPushed(t *testing.T) {
+	e := newTestEngine(t)
+	_, work := setupSyncRepos(t)
+	message := runGitIn(t, work, "log", "-1", "--format=%s")
+	writeWorkFile(t, work, "README.md", "补充\n")
+	// 强制推送需要确认
+	e.Stdin = answerFile(t, "y\n")
+
+	output := runQuickCommit(t, e, "--amend")
+
+	if !strings.Contains(output, "git commit --amend --no-edit") || !strings.Contains(output, "git push --force-with-lease") {
+		t.Errorf("修改已推送的提交时应该沿用提交信息并使用 --force-with-lease，实际输出:\n%s", output)
+	}
+	if got := runGitIn(t, work, "log", "-1", "--format=%s"); got != message {
+		t.Errorf("省略提交信息时应该沿用原信息 %q，得到 %q", message, got)
+	}
+	if runGitIn(t, work, "rev-parse", "HEAD") != runGitIn(t, work, "rev-parse", "origin/main") {
+		t.Error("修改后的提交应该推送到远程")
+	}
+}
@@ -0,0 +1,120 @@
+package xgit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 显示基本使用说明
+func (e *Engine) showUsage() {
+	fmt.Fprintln(e.Stdout, "xgit - 中文拼音首字母的Git命令工具")
+	fmt.Fprintln(e.Stdout)
+	fmt.Fprintln(e.Stdout, "用法:")
+	fmt.Fprintln(e.Stdout, "  xgit <拼音命令> [参数...]     # 使用拼音首字母命令")
+	fmt.Fprintln(e.Stdout, "  xgit git <git命令> [参数...]  # 直接执行git命令")
+	fmt.Fprintln(e.Stdout, "  xgit bz [命令]               # 查看帮助")
+	fmt.Fprintln(e.Stdout, "  xgit -n <拼音命令> [参数...]  # 预演：只打印将要执行的git命令")
+	fmt.Fprintln(e.Stdout, "  xgit -y <拼音命令> [参数...]  # 跳过破坏性操作的确认")
+	fmt.Fprintln(e.Stdout, "  xgit completion bash|zsh|fish # 生成shell补全脚本")
+	fmt.Fprintln(e.Stdout, "  xgit pz jc [配置文件...]      # 检查配置文件")
+	fmt.Fprintln(e.Stdout, "  xgit bm tj <别名> -- <参数>   # 添加自定义别名")
+	fmt.Fprintln(e.Stdout)
+	fmt.Fprintln(e.Stdout, "常用命令:")
+	fmt.Fprintln(e.Stdout, "  xgit kl <url>      # 克隆仓库")
+	fmt.Fprintln(e.Stdout, "  xgit tja .         # 添加所有文件")
+	fmt.Fprintln(e.Stdout, "  xgit tj -m 'msg'   # 提交更改")
+	fmt.Fprintln(e.Stdout, "  xgit ts            # 推送代码")
+	fmt.Fprintln(e.Stdout, "  xgit lq            # 拉取代码")
+	fmt.Fprintln(e.Stdout)
+	fmt.Fprintln(e.Stdout, "运行 'xgit bz' 查看完整命令列表")
+}
+
+// 显示帮助信息，bz --list 的参数不正确时返回 ErrInvalidArgs
+func (e *Engine) showHelp(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(e.Stdout, "xgit 命令列表:")
+		fmt.Fprintln(e.Stdout)
+
+		for _, category := range e.categoryOrder {
+			fmt.Fprintf(e.Stdout, "【%s】\n", category)
+			for _, cmd := range e.commandCategories[category] {
+				if help, exists := e.commandHelp[cmd]; exists {
+					fmt.Fprintf(e.Stdout, "  %-6s %s\n", cmd, help)
+				}
+			}
+			fmt.Fprintln(e.Stdout)
+		}
+
+		fmt.Fprintln(e.Stdout, "使用 'xgit bz <命令>' 查看具体命令用法")
+		fmt.Fprintln(e.Stdout, "使用 'xgit bz --git <命令>' 查看对应的git命令")
+		fmt.Fprintln(e.Stdout, "使用 'xgit bz --list' 查看完整映射表")
+		return nil
+	}
+
+	targetCmd := args[0]
+	if targetCmd == "--list" {
+		return e.showMappingList(args[1:])
+	}
+	if len(args) > 1 && args[0] == "--git" {
+		targetCmd = args[1]
+		e.showGitEquivalent(targetCmd)
+		return nil
+	}
+
+	targetCmd = e.primaryName(targetCmd)
+	if help, exists := e.commandHelp[targetCmd]; exists {
+		fmt.Fprintf(e.Stdout, "命令: %s\n", targetCmd)
+		fmt.Fprintf(e.Stdout, "说明: %s\n", help)
+		if names := e.config.commandNames(targetCmd); len(names) > 0 {
+			fmt.Fprintf(e.Stdout, "其他名称: %s\n", strings.Join(names, ", "))
+		}
+		fmt.Fprintln(e.Stdout)
+
+		// 显示用法示例
+		e.showUsageExamples(targetCmd)
+	} else {
+		fmt.Fprintf(e.Stdout, "未知命令: %s\n", targetCmd)
+		fmt.Fprintln(e.Stdout, "运行 'xgit bz' 查看所有可用命令")
+	}
+	return nil
+}
+
+// 显示git等价命令
+func (e *Engine) showGitEquivalent(command string) {
+	command = e.primaryName(command)
+	if gitCmd, exists := e.commandMap[command]; exists {
+		fmt.Fprintf(e.Stdout, "%s → %s\n", command, formatGitCommand(gitCmd))
+	} else if _, exists := e.compositeCommands[command]; exists {
+		fmt.Fprintf(e.Stdout, "%s → 复合命令:\n", command)
+		for i, cmd := range e.compositeCommands[command] {
+			fmt.Fprintf(e.Stdout, "  %d. %s\n", i+1, formatGitCommand(cmd))
+		}
+	} else {
+		fmt.Fprintf(e.Stdout, "未知命令: %s\n", command)
+	}
+}
+
+// 显示用法示例，没有配置示例的命令不输出任何内容
+func (e *Engine) showUsageExamples(command string) {
+	examples := e.commandExamples[command]
+	if len(examples) == 0 {
+		return
+	}
+
+	width := 0
+	for _, example := range examples {
+		if w := displayWidth(example.Command); w > width {
+			width = w
+		}
+	}
+
+	fmt.Fprintln(e.Stdout, "用法示例:")
+	for _, example := range examples {
+		if example.Description == "" {
+			fmt.Fprintf(e.Stdout, "  %s\n", example.Command)
+			continue
+		}
+		padding := strings.Repeat(" ", width-displayWidth(example.Command))
+		fmt.Fprintf(e.Stdout, "  %s%s  # %s\n", example.Command, padding, example.Description)
+	}
+}
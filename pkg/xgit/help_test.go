@@ -1,32 +1,46 @@
-package main
+package xgit
 
 import (
 	"bytes"
 	"io"
-	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
+// 使用内置默认配置的引擎，输出默认丢弃，确认提示读到空输入
+func newTestEngine(tb testing.TB) *Engine {
+	tb.Helper()
+	cfg, err := DefaultConfig()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	e, err := New(cfg)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	e.Stdin = strings.NewReader("")
+	e.Stdout = io.Discard
+	e.Stderr = io.Discard
+	e.Sleep = func(time.Duration) {}
+	return e
+}
+
 // 捕获输出的帮助函数
-func captureOutput(f func()) string {
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+func captureOutput(e *Engine, f func()) string {
+	old := e.Stdout
+	var buf bytes.Buffer
+	e.Stdout = &buf
+	defer func() { e.Stdout = old }()
 
 	f()
-
-	w.Close()
-	os.Stdout = old
-
-	var buf bytes.Buffer
-	io.Copy(&buf, r)
 	return buf.String()
 }
 
 func TestShowUsage(t *testing.T) {
-	output := captureOutput(func() {
-		showUsage()
+	e := newTestEngine(t)
+	output := captureOutput(e, func() {
+		e.showUsage()
 	})
 
 	// 检查基本元素是否存在
@@ -53,8 +67,9 @@ func TestShowUsage(t *testing.T) {
 }
 
 func TestShowHelp_EmptyArgs(t *testing.T) {
-	output := captureOutput(func() {
-		showHelp([]string{})
+	e := newTestEngine(t)
+	output := captureOutput(e, func() {
+		e.showHelp([]string{})
 	})
 
 	// 检查帮助列表的基本元素
@@ -88,7 +103,27 @@ func TestShowHelp_EmptyArgs(t *testing.T) {
 	}
 }
 
+func TestShowHelp_StableOrder(t *testing.T) {
+	e := newTestEngine(t)
+	first := captureOutput(e, func() { e.showHelp([]string{}) })
+	for i := 0; i < 5; i++ {
+		if output := captureOutput(e, func() { e.showHelp([]string{}) }); output != first {
+			t.Fatalf("多次运行帮助的输出应该一致\n第一次:\n%s\n第 %d 次:\n%s", first, i+2, output)
+		}
+	}
+
+	previous := -1
+	for _, category := range e.categoryOrder {
+		index := strings.Index(first, "【"+category+"】")
+		if index <= previous {
+			t.Errorf("分类 %s 的显示顺序不正确", category)
+		}
+		previous = index
+	}
+}
+
 func TestShowHelp_SpecificCommand(t *testing.T) {
+	e := newTestEngine(t)
 	tests := []struct {
 		name     string
 		command  string
@@ -128,8 +163,8 @@ func TestShowHelp_SpecificCommand(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			output := captureOutput(func() {
-				showHelp([]string{tt.command})
+			output := captureOutput(e, func() {
+				e.showHelp([]string{tt.command})
 			})
 
 			for _, expected := range tt.expected {
@@ -142,8 +177,9 @@ func TestShowHelp_SpecificCommand(t *testing.T) {
 }
 
 func TestShowHelp_UnknownCommand(t *testing.T) {
-	output := captureOutput(func() {
-		showHelp([]string{"invalidcommand"})
+	e := newTestEngine(t)
+	output := captureOutput(e, func() {
+		e.showHelp([]string{"invalidcommand"})
 	})
 
 	expectedElements := []string{
@@ -159,6 +195,7 @@ func TestShowHelp_UnknownCommand(t *testing.T) {
 }
 
 func TestShowGitEquivalent(t *testing.T) {
+	e := newTestEngine(t)
 	tests := []struct {
 		name     string
 		command  string
@@ -193,8 +230,8 @@ func TestShowGitEquivalent(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			output := captureOutput(func() {
-				showGitEquivalent(tt.command)
+			output := captureOutput(e, func() {
+				e.showGitEquivalent(tt.command)
 			})
 
 			for _, expected := range tt.expected {
@@ -208,8 +245,9 @@ func TestShowGitEquivalent(t *testing.T) {
 }
 
 func TestShowHelp_GitEquivalent(t *testing.T) {
-	output := captureOutput(func() {
-		showHelp([]string{"--git", "kl"})
+	e := newTestEngine(t)
+	output := captureOutput(e, func() {
+		e.showHelp([]string{"--git", "kl"})
 	})
 
 	if !strings.Contains(output, "kl → git clone") {
@@ -218,6 +256,7 @@ func TestShowHelp_GitEquivalent(t *testing.T) {
 }
 
 func TestShowUsageExamples(t *testing.T) {
+	e := newTestEngine(t)
 	tests := []struct {
 		name     string
 		command  string
@@ -254,8 +293,8 @@ func TestShowUsageExamples(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			output := captureOutput(func() {
-				showUsageExamples(tt.command)
+			output := captureOutput(e, func() {
+				e.showUsageExamples(tt.command)
 			})
 
 			for _, expected := range tt.expected {
@@ -268,10 +307,22 @@ func TestShowUsageExamples(t *testing.T) {
 	}
 }
 
+func TestShowUsageExamples_Description(t *testing.T) {
+	e := newTestEngine(t)
+	output := captureOutput(e, func() {
+		e.showUsageExamples("ht")
+	})
+
+	if !strings.Contains(output, "xgit ht --hard HEAD~2  # 丢弃最近两次提交及其修改") {
+		t.Errorf("示例应该带有对齐的说明，实际输出:\n%s", output)
+	}
+}
+
 func TestShowUsageExamples_NoExample(t *testing.T) {
+	e := newTestEngine(t)
 	// 测试没有特定示例的命令
-	output := captureOutput(func() {
-		showUsageExamples("ts")
+	output := captureOutput(e, func() {
+		e.showUsageExamples("ts")
 	})
 
 	// 对于没有特定示例的命令，不应该有任何输出
@@ -1,22 +1,15 @@
-package main
+package xgit
 
 import (
-	"os"
 	"strings"
 	"testing"
 )
 
-// 集成测试：测试主程序的参数处理
+// 集成测试：测试命令行参数的处理
 func TestMain_NoArgs(t *testing.T) {
-	// 备份原始参数
-	oldArgs := os.Args
-	defer func() { os.Args = oldArgs }()
-
-	// 设置测试参数（只有程序名）
-	os.Args = []string{"xgit"}
-
-	output := captureOutput(func() {
-		main()
+	e := newTestEngine(t)
+	output := captureOutput(e, func() {
+		e.Run(nil)
 	})
 
 	// 检查是否显示了使用说明
@@ -26,17 +19,13 @@ func TestMain_NoArgs(t *testing.T) {
 }
 
 func TestMain_HelpCommand(t *testing.T) {
-	oldArgs := os.Args
-	defer func() { os.Args = oldArgs }()
-
+	e := newTestEngine(t)
 	testCases := []string{"bz", "help"}
 
 	for _, helpCmd := range testCases {
 		t.Run("help_command_"+helpCmd, func(t *testing.T) {
-			os.Args = []string{"xgit", helpCmd}
-
-			output := captureOutput(func() {
-				main()
+			output := captureOutput(e, func() {
+				e.Run([]string{helpCmd})
 			})
 
 			// 检查是否显示了命令列表
@@ -48,13 +37,9 @@ func TestMain_HelpCommand(t *testing.T) {
 }
 
 func TestMain_SpecificHelpCommand(t *testing.T) {
-	oldArgs := os.Args
-	defer func() { os.Args = oldArgs }()
-
-	os.Args = []string{"xgit", "bz", "kl"}
-
-	output := captureOutput(func() {
-		main()
+	e := newTestEngine(t)
+	output := captureOutput(e, func() {
+		e.Run([]string{"bz", "kl"})
 	})
 
 	expectedElements := []string{
@@ -71,13 +56,9 @@ func TestMain_SpecificHelpCommand(t *testing.T) {
 }
 
 func TestMain_GitEquivalentCommand(t *testing.T) {
-	oldArgs := os.Args
-	defer func() { os.Args = oldArgs }()
-
-	os.Args = []string{"xgit", "bz", "--git", "kl"}
-
-	output := captureOutput(func() {
-		main()
+	e := newTestEngine(t)
+	output := captureOutput(e, func() {
+		e.Run([]string{"bz", "--git", "kl"})
 	})
 
 	if !strings.Contains(output, "kl → git clone") {
@@ -85,8 +66,35 @@ func TestMain_GitEquivalentCommand(t *testing.T) {
 	}
 }
 
+func TestMain_DryRun(t *testing.T) {
+	e := newTestEngine(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"长选项", []string{"--dry-run", "ht", "--hard", "HEAD~1"}, "git reset --hard HEAD~1\n"},
+		{"短选项", []string{"-n", "tj", "-m", "a b"}, "git commit -m 'a b'\n"},
+		{"原生git命令", []string{"-n", "git", "status"}, "git status\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := captureOutput(e, func() {
+				e.Run(tt.args)
+			})
+
+			if output != tt.expected {
+				t.Errorf("预演输出不正确，期望 %q，得到 %q", tt.expected, output)
+			}
+		})
+	}
+}
+
 // 测试完整的命令流程（不实际执行git）
 func TestCompleteCommandFlow(t *testing.T) {
+	e := newTestEngine(t)
 	tests := []struct {
 		name        string
 		args        []string
@@ -95,31 +103,31 @@ func TestCompleteCommandFlow(t *testing.T) {
 	}{
 		{
 			name:        "显示基本帮助",
-			args:        []string{"xgit"},
+			args:        nil,
 			expectError: false,
 			contains:    []string{"中文拼音首字母的Git命令工具", "用法:"},
 		},
 		{
 			name:        "显示命令列表",
-			args:        []string{"xgit", "bz"},
+			args:        []string{"bz"},
 			expectError: false,
 			contains:    []string{"命令列表:", "【仓库操作】", "【文件操作】"},
 		},
 		{
 			name:        "显示特定命令帮助",
-			args:        []string{"xgit", "bz", "tj"},
+			args:        []string{"bz", "tj"},
 			expectError: false,
 			contains:    []string{"命令: tj", "提交更改", "用法示例:"},
 		},
 		{
 			name:        "显示git等价命令",
-			args:        []string{"xgit", "bz", "--git", "kstj"},
+			args:        []string{"bz", "--git", "kstj"},
 			expectError: false,
 			contains:    []string{"复合命令:", "git add .", "git commit -m", "git push"},
 		},
 		{
 			name:        "未知命令帮助",
-			args:        []string{"xgit", "bz", "unknown"},
+			args:        []string{"bz", "unknown"},
 			expectError: false,
 			contains:    []string{"未知命令: unknown"},
 		},
@@ -127,13 +135,8 @@ func TestCompleteCommandFlow(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			oldArgs := os.Args
-			defer func() { os.Args = oldArgs }()
-
-			os.Args = tt.args
-
-			output := captureOutput(func() {
-				main()
+			output := captureOutput(e, func() {
+				e.Run(tt.args)
 			})
 
 			for _, expected := range tt.contains {
@@ -148,19 +151,20 @@ func TestCompleteCommandFlow(t *testing.T) {
 
 // 测试命令映射的完整性
 func TestCommandMappingCompleteness(t *testing.T) {
+	e := newTestEngine(t)
 	// 检查所有在分类中的命令都有对应的映射或帮助
-	for category, commands := range commandCategories {
+	for category, commands := range e.commandCategories {
 		for _, cmd := range commands {
 			t.Run("mapping_"+category+"_"+cmd, func(t *testing.T) {
 				hasMapping := false
 
 				// 检查基本命令映射
-				if _, exists := commandMap[cmd]; exists {
+				if _, exists := e.commandMap[cmd]; exists {
 					hasMapping = true
 				}
 
 				// 检查复合命令映射
-				if _, exists := compositeCommands[cmd]; exists {
+				if _, exists := e.compositeCommands[cmd]; exists {
 					hasMapping = true
 				}
 
@@ -169,7 +173,7 @@ func TestCommandMappingCompleteness(t *testing.T) {
 				}
 
 				// 检查是否有帮助信息
-				if _, exists := commandHelp[cmd]; !exists {
+				if _, exists := e.commandHelp[cmd]; !exists {
 					t.Errorf("分类 %s 中的命令 %s 没有帮助信息", category, cmd)
 				}
 			})
@@ -179,37 +183,38 @@ func TestCommandMappingCompleteness(t *testing.T) {
 
 // 测试命令一致性
 func TestCommandConsistency(t *testing.T) {
+	e := newTestEngine(t)
 	// 所有commandMap中的命令都应该在commandHelp中
-	for cmd := range commandMap {
-		if _, exists := commandHelp[cmd]; !exists {
+	for cmd := range e.commandMap {
+		if _, exists := e.commandHelp[cmd]; !exists {
 			t.Errorf("命令 %s 在commandMap中但不在commandHelp中", cmd)
 		}
 	}
 
 	// 所有compositeCommands中的命令都应该在commandHelp中
-	for cmd := range compositeCommands {
-		if _, exists := commandHelp[cmd]; !exists {
+	for cmd := range e.compositeCommands {
+		if _, exists := e.commandHelp[cmd]; !exists {
 			t.Errorf("复合命令 %s 在compositeCommands中但不在commandHelp中", cmd)
 		}
 	}
 
 	// 所有在commandCategories中的命令都应该存在于映射中
 	allCategorizedCommands := make(map[string]bool)
-	for _, commands := range commandCategories {
+	for _, commands := range e.commandCategories {
 		for _, cmd := range commands {
 			allCategorizedCommands[cmd] = true
 		}
 	}
 
 	// 检查基本命令
-	for cmd := range commandMap {
+	for cmd := range e.commandMap {
 		if !allCategorizedCommands[cmd] {
 			t.Errorf("基本命令 %s 没有被分类", cmd)
 		}
 	}
 
 	// 检查复合命令
-	for cmd := range compositeCommands {
+	for cmd := range e.compositeCommands {
 		if !allCategorizedCommands[cmd] {
 			t.Errorf("复合命令 %s 没有被分类", cmd)
 		}
@@ -218,6 +223,7 @@ func TestCommandConsistency(t *testing.T) {
 
 // 性能测试：测试命令查找性能
 func BenchmarkMainCommandLookup(b *testing.B) {
+	e := newTestEngine(b)
 	testCommands := []string{"kl", "tj", "ts", "lq", "kstj", "bz"}
 
 	b.ResetTimer()
@@ -232,9 +238,9 @@ func BenchmarkMainCommandLookup(b *testing.B) {
 			// git命令
 		default:
 			// 拼音命令查找
-			_, compositeExists := compositeCommands[cmd]
-			_, basicExists := commandMap[cmd]
-			gitExists := isGitCommand(cmd)
+			_, compositeExists := e.compositeCommands[cmd]
+			_, basicExists := e.commandMap[cmd]
+			gitExists := e.isGitCommand(cmd)
 			_ = compositeExists || basicExists || gitExists
 		}
 	}
@@ -242,16 +248,17 @@ func BenchmarkMainCommandLookup(b *testing.B) {
 
 // 测试内存使用情况
 func TestMemoryUsage(t *testing.T) {
+	e := newTestEngine(t)
 	// 检查映射表的大小是否合理
-	totalCommands := len(commandMap) + len(compositeCommands)
-	totalHelp := len(commandHelp)
+	totalCommands := len(e.commandMap) + len(e.compositeCommands)
+	totalHelp := len(e.commandHelp)
 	totalCategories := 0
-	for _, commands := range commandCategories {
+	for _, commands := range e.commandCategories {
 		totalCategories += len(commands)
 	}
 
-	t.Logf("命令映射数量: %d", len(commandMap))
-	t.Logf("复合命令数量: %d", len(compositeCommands))
+	t.Logf("命令映射数量: %d", len(e.commandMap))
+	t.Logf("复合命令数量: %d", len(e.compositeCommands))
 	t.Logf("总命令数量: %d", totalCommands)
 	t.Logf("帮助信息数量: %d", totalHelp)
 	t.Logf("分类命令总数: %d", totalCategories)
@@ -0,0 +1,191 @@
+package xgit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 日志最多保留的操作数
+const maxJournalEntries = 100
+
+// 仓库在某一时刻的状态，用于撤销时恢复
+type repoState struct {
+	Head       string            `json:"head"`             // HEAD 指向的提交，空仓库为空
+	Branch     string            `json:"branch,omitempty"` // 当前分支，分离头指针时为空
+	Branches   map[string]string `json:"branches"`         // 本地分支及其提交
+	Stash      string            `json:"stash,omitempty"`  // 最新储藏的提交
+	StashCount int               `json:"stash_count"`
+}
+
+// 日志中的一次xgit操作
+type journalEntry struct {
+	Time    time.Time `json:"time"`
+	Command []string  `json:"command"` // 用户输入的xgit参数
+	Before  repoState `json:"before"`
+	After   repoState `json:"after"`
+	Undone  bool      `json:"undone,omitempty"`
+}
+
+// 是否为撤销命令（使用 undo 处理器的复合命令）
+func (e *Engine) isUndoCommand(command string) bool {
+	if _, exists := e.Resolve(command); !exists {
+		command, _ = e.completePrefix(command)
+	}
+	spec, exists := e.config.CompositeCommands[e.primaryName(command)]
+	return exists && spec.Handler == "undo"
+}
+
+// 读取仓库当前状态，不在仓库中时返回错误。每个xgit命令执行前后都会调用，
+// 通常只需要一次 for-each-ref：当前分支由 %(HEAD) 标出，
+// 只有分离头指针、空仓库或有储藏时才需要额外的git命令
+func (e *Engine) captureRepoState() (repoState, error) {
+	state := repoState{Branches: make(map[string]string)}
+	refs, err := e.gitOutput("for-each-ref", "--format=%(refname) %(objectname) %(HEAD)", "refs/heads", "refs/stash")
+	if err != nil {
+		return state, err
+	}
+	for _, line := range outputLines(refs) {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		ref, sha := fields[0], fields[1]
+		if ref == "refs/stash" {
+			state.Stash = sha
+			continue
+		}
+		name := strings.TrimPrefix(ref, "refs/heads/")
+		state.Branches[name] = sha
+		if len(fields) > 2 && fields[2] == "*" {
+			state.Branch, state.Head = name, sha
+		}
+	}
+
+	if state.Branch == "" {
+		state.Head, _ = e.gitOutput("rev-parse", "--verify", "-q", "HEAD")
+		if state.Head == "" {
+			// 空仓库中 HEAD 指向还没有提交的分支
+			state.Branch, _ = e.gitOutput("symbolic-ref", "-q", "--short", "HEAD")
+		}
+	}
+
+	if state.Stash != "" {
+		if stashes, err := e.gitOutput("stash", "list", "--format=%H"); err == nil {
+			state.StashCount = len(outputLines(stashes))
+		}
+	}
+	return state, nil
+}
+
+// 两个状态的引用是否相同
+func (s repoState) sameRefs(other repoState) bool {
+	if s.Head != other.Head || s.Branch != other.Branch || s.Stash != other.Stash || s.StashCount != other.StashCount {
+		return false
+	}
+	if len(s.Branches) != len(other.Branches) {
+		return false
+	}
+	for name, sha := range s.Branches {
+		if other.Branches[name] != sha {
+			return false
+		}
+	}
+	return true
+}
+
+// 日志文件路径: .git/xgit/journal
+func (e *Engine) journalPath() (string, error) {
+	gitDir, err := e.gitOutput("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitDir, "xgit", "journal"), nil
+}
+
+// 读取日志，每行一条记录，按时间先后排列
+func (e *Engine) readJournal() ([]journalEntry, error) {
+	path, err := e.journalPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []journalEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry journalEntry
+		// 损坏的行直接跳过，不影响其余记录
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// 写回日志，只保留最近的 maxJournalEntries 条
+func (e *Engine) writeJournal(entries []journalEntry) error {
+	path, err := e.journalPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if len(entries) > maxJournalEntries {
+		entries = entries[len(entries)-maxJournalEntries:]
+	}
+
+	var buf strings.Builder
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return os.WriteFile(path, []byte(buf.String()), 0o644)
+}
+
+// 执行一次xgit操作并记录执行前后的仓库状态。预演模式、不在仓库中
+// 或状态没有变化时不记录；记录失败不影响命令本身的结果
+func (e *Engine) journalOperation(command []string, run func() error) error {
+	if e.DryRun {
+		return run()
+	}
+	before, err := e.captureRepoState()
+	if err != nil {
+		return run()
+	}
+
+	runErr := run()
+
+	after, err := e.captureRepoState()
+	if err != nil || after.sameRefs(before) {
+		return runErr
+	}
+	entries, err := e.readJournal()
+	if err != nil {
+		return runErr
+	}
+	entries = append(entries, journalEntry{
+		Time:    time.Now(),
+		Command: command,
+		Before:  before,
+		After:   after,
+	})
+	_ = e.writeJournal(entries)
+	return runErr
+}
@@ -0,0 +1,127 @@
+package xgit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// 保留原有格式修改JSON文件：只替换涉及的那一段文本，其余内容原样保留
+
+// offset 所在行的行首缩进
+func lineIndent(data []byte, offset int) string {
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+	end := start
+	for end < offset && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[start:end])
+}
+
+// 将值编码为JSON，换行后的内容以 prefix 开头、每层缩进 indent
+func marshalIndented(value any, prefix, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent(prefix, indent)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// 用 text 替换 data[start:end]
+func splice(data []byte, start, end int, text []byte) []byte {
+	out := make([]byte, 0, len(data)-(end-start)+len(text))
+	out = append(out, data[:start]...)
+	out = append(out, text...)
+	return append(out, data[end:]...)
+}
+
+// 查找 path 处的对象
+func indexObject(data []byte, path string) (*jsonIndex, error) {
+	index, err := indexJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	start, exists := index.starts[path]
+	if !exists || data[start] != '{' {
+		return nil, fmt.Errorf("%s 不是对象", jsonPathName(path))
+	}
+	return index, nil
+}
+
+// 设置对象 parent 中键 key 的值：已存在时替换原来的值，否则追加到对象末尾，
+// 缩进与对象中已有的成员一致
+func setJSONMember(data []byte, parent, key string, value any) ([]byte, error) {
+	index, err := indexObject(data, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	objectStart := index.starts[parent]
+	objectIndent := lineIndent(data, objectStart)
+	memberIndent, unit := objectIndent+"  ", "  "
+	members := index.members[parent]
+	if len(members) > 0 {
+		first := index.offsets[members[0]]
+		if indent := lineIndent(data, first); len(indent) == first-(bytes.LastIndexByte(data[:first], '\n')+1) {
+			memberIndent = indent
+			if len(indent) > len(objectIndent) {
+				unit = indent[len(objectIndent):]
+			}
+		}
+	}
+
+	child := parent + "/" + key
+	if start, exists := index.starts[child]; exists {
+		encoded, err := marshalIndented(value, lineIndent(data, index.offsets[child]), unit)
+		if err != nil {
+			return nil, err
+		}
+		return splice(data, start, index.ends[child], encoded), nil
+	}
+
+	encodedKey, err := marshalIndented(key, "", "")
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := marshalIndented(value, memberIndent, unit)
+	if err != nil {
+		return nil, err
+	}
+	member := slices.Concat([]byte(memberIndent), encodedKey, []byte(": "), encoded)
+
+	if len(members) > 0 {
+		last := index.ends[members[len(members)-1]]
+		return splice(data, last, last, slices.Concat([]byte(",\n"), member)), nil
+	}
+	// 空对象：成员单独占一行
+	return splice(data, objectStart+1, index.ends[parent]-1, slices.Concat([]byte("\n"), member, []byte("\n"+objectIndent))), nil
+}
+
+// 删除对象 parent 中的键 key 及其分隔的逗号，键不存在时返回 false
+func deleteJSONMember(data []byte, parent, key string) ([]byte, bool, error) {
+	index, err := indexObject(data, parent)
+	if err != nil {
+		return nil, false, err
+	}
+
+	members := index.members[parent]
+	child := parent + "/" + key
+	i := slices.Index(members, child)
+	switch {
+	case i < 0:
+		return data, false, nil
+	case i > 0:
+		// 连同前一个成员之后的逗号一起删除
+		return splice(data, index.ends[members[i-1]], index.ends[child], nil), true, nil
+	case len(members) > 1:
+		// 第一个成员：删除到下一个成员的键之前
+		return splice(data, index.offsets[child], index.offsets[members[1]], nil), true, nil
+	default:
+		// 唯一的成员：留下空对象
+		return splice(data, index.starts[parent]+1, index.ends[parent]-1, nil), true, nil
+	}
+}
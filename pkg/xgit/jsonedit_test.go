@@ -0,0 +1,92 @@
+package xgit
+
+import (
+	"testing"
+)
+
+func TestSetJSONMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		parent   string
+		key      string
+		value    any
+		expected string
+	}{
+		{
+			"追加到已有成员之后并沿用缩进",
+			"{\n    \"settings\": {\"autocorrect\": 10},\n    \"commands\": {\n        \"a\": {\"args\": [\"log\"]}\n    }\n}\n",
+			"/commands", "b", map[string]int{"n": 1},
+			"{\n    \"settings\": {\"autocorrect\": 10},\n    \"commands\": {\n        \"a\": {\"args\": [\"log\"]},\n        \"b\": {\n            \"n\": 1\n        }\n    }\n}\n",
+		},
+		{
+			"替换已有的值",
+			"{\n  \"commands\": {\n    \"a\": 1,\n    \"b\": 2\n  }\n}\n",
+			"/commands", "a", 3,
+			"{\n  \"commands\": {\n    \"a\": 3,\n    \"b\": 2\n  }\n}\n",
+		},
+		{
+			"空对象",
+			"{\n  \"commands\": {}\n}\n",
+			"/commands", "a", []string{"x"},
+			"{\n  \"commands\": {\n    \"a\": [\n      \"x\"\n    ]\n  }\n}\n",
+		},
+		{
+			"添加到根对象",
+			"{\"settings\": {}}",
+			"", "commands", map[string]int{},
+			"{\"settings\": {},\n  \"commands\": {}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := setJSONMember([]byte(tt.data), tt.parent, tt.key, tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("结果不正确\n期望:\n%s\n得到:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSetJSONMember_NotObject(t *testing.T) {
+	if _, err := setJSONMember([]byte(`{"commands": []}`), "/commands", "a", 1); err == nil {
+		t.Error("目标不是对象时应该返回错误")
+	}
+}
+
+func TestDeleteJSONMember(t *testing.T) {
+	data := "{\n  \"commands\": {\n    \"a\": 1,\n    \"b\": {\"x\": [1, 2]},\n    \"c\": 3\n  }\n}\n"
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{"第一个成员", "a", "{\n  \"commands\": {\n    \"b\": {\"x\": [1, 2]},\n    \"c\": 3\n  }\n}\n"},
+		{"中间的成员", "b", "{\n  \"commands\": {\n    \"a\": 1,\n    \"c\": 3\n  }\n}\n"},
+		{"最后的成员", "c", "{\n  \"commands\": {\n    \"a\": 1,\n    \"b\": {\"x\": [1, 2]}\n  }\n}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, found, err := deleteJSONMember([]byte(data), "/commands", tt.key)
+			if err != nil || !found {
+				t.Fatalf("删除失败: %v, %v", found, err)
+			}
+			if string(result) != tt.expected {
+				t.Errorf("结果不正确\n期望:\n%s\n得到:\n%s", tt.expected, result)
+			}
+		})
+	}
+
+	result, found, _ := deleteJSONMember([]byte(`{"commands": {"a": 1}}`), "/commands", "a")
+	if !found || string(result) != `{"commands": {}}` {
+		t.Errorf("删除唯一的成员后应该留下空对象，得到 %s", result)
+	}
+	if _, found, _ := deleteJSONMember([]byte(data), "/commands", "z"); found {
+		t.Error("不存在的键不应该被删除")
+	}
+}
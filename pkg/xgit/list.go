@@ -0,0 +1,176 @@
+package xgit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 原生git命令在映射表中的分类
+const passthroughCategory = "原生git命令"
+
+// 映射表中的一行
+type mappingRow struct {
+	Alias    string `json:"alias"`
+	Pinyin   string `json:"pinyin"`
+	Category string `json:"category"`
+	Git      string `json:"git"`
+}
+
+// 说明中括号内的拼音，如 "克隆仓库 (ke long) → git clone"
+var descriptionPinyinPattern = regexp.MustCompile(`\(([a-z]+(?: [a-z]+)*)\)`)
+
+// 从命令说明中提取拼音
+func pinyinOf(description string) string {
+	if m := descriptionPinyinPattern.FindStringSubmatch(description); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// 生成完整映射表：按分类顺序列出基本命令和复合命令，最后是原生git命令
+func (e *Engine) mappingRows() []mappingRow {
+	var rows []mappingRow
+
+	for _, category := range e.categoryOrder {
+		for _, alias := range e.commandCategories[category] {
+			if cmd, exists := e.config.Commands[alias]; exists {
+				rows = append(rows, mappingRow{
+					Alias:    alias,
+					Pinyin:   pinyinOf(cmd.Description),
+					Category: cmd.Category,
+					Git:      formatGitCommand(cmd.Args),
+				})
+				continue
+			}
+
+			cmd := e.config.CompositeCommands[alias]
+			steps := make([]string, 0, len(cmd.Steps))
+			for _, step := range cmd.Steps {
+				steps = append(steps, formatGitCommand(step))
+			}
+			rows = append(rows, mappingRow{
+				Alias:    alias,
+				Pinyin:   pinyinOf(cmd.Description),
+				Category: cmd.Category,
+				Git:      strings.Join(steps, " && "),
+			})
+		}
+	}
+
+	for _, gitCmd := range e.gitCommands {
+		rows = append(rows, mappingRow{
+			Alias:    gitCmd,
+			Category: passthroughCategory,
+			Git:      formatGitCommand([]string{gitCmd}),
+		})
+	}
+
+	return rows
+}
+
+// 显示完整映射表，args 为 --list 之后的参数。参数或格式不正确时返回 ErrInvalidArgs，
+// 把输出粘贴到其他地方的脚本可以据此发现错误
+func (e *Engine) showMappingList(args []string) error {
+	format := "text"
+	for i := 0; i < len(args); i++ {
+		switch {
+		case strings.HasPrefix(args[i], "--format="):
+			format = strings.TrimPrefix(args[i], "--format=")
+		case args[i] == "--format" && i+1 < len(args):
+			format = args[i+1]
+			i++
+		default:
+			fmt.Fprintf(e.Stdout, "错误: 未知参数: %s\n", args[i])
+			fmt.Fprintln(e.Stdout, "用法: xgit bz --list [--format=text|json|markdown|csv]")
+			return ErrInvalidArgs
+		}
+	}
+
+	rows := e.mappingRows()
+	switch format {
+	case "text":
+		e.printMappingText(rows)
+	case "json":
+		data, _ := json.MarshalIndent(rows, "", "  ")
+		fmt.Fprintln(e.Stdout, string(data))
+	case "markdown", "md":
+		e.printMappingMarkdown(rows)
+	case "csv":
+		w := csv.NewWriter(e.Stdout)
+		w.Write([]string{"alias", "pinyin", "category", "git"})
+		for _, row := range rows {
+			w.Write([]string{row.Alias, row.Pinyin, row.Category, row.Git})
+		}
+		w.Flush()
+	default:
+		fmt.Fprintf(e.Stdout, "错误: 不支持的输出格式: %s（可选: text, json, markdown, csv）\n", format)
+		return ErrInvalidArgs
+	}
+	return nil
+}
+
+// 映射表的表头
+var mappingHeader = []string{"命令", "拼音", "分类", "git命令"}
+
+// 以对齐的文本表格输出
+func (e *Engine) printMappingText(rows []mappingRow) {
+	widths := make([]int, len(mappingHeader))
+	cells := make([][]string, 0, len(rows)+1)
+	cells = append(cells, mappingHeader)
+	for _, row := range rows {
+		cells = append(cells, []string{row.Alias, row.Pinyin, row.Category, row.Git})
+	}
+	for _, line := range cells {
+		for i, cell := range line {
+			if w := displayWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	for _, line := range cells {
+		var b strings.Builder
+		for i, cell := range line {
+			b.WriteString(cell)
+			if i < len(line)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+			}
+		}
+		fmt.Fprintln(e.Stdout, b.String())
+	}
+}
+
+// 以Markdown表格输出
+func (e *Engine) printMappingMarkdown(rows []mappingRow) {
+	fmt.Fprintf(e.Stdout, "| %s |\n", strings.Join(mappingHeader, " | "))
+	fmt.Fprintln(e.Stdout, "| --- | --- | --- | --- |")
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "|", `\|`)
+	}
+	for _, row := range rows {
+		fmt.Fprintf(e.Stdout, "| `%s` | %s | %s | `%s` |\n", escape(row.Alias), escape(row.Pinyin), escape(row.Category), escape(row.Git))
+	}
+}
+
+// 计算字符串在终端中的显示宽度，中日韩字符占两列
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r),
+			unicode.Is(unicode.Hangul, r),
+			unicode.Is(unicode.Hiragana, r),
+			unicode.Is(unicode.Katakana, r),
+			r >= 0x3000 && r <= 0x303F, // 中日韩标点
+			r >= 0xFF00 && r <= 0xFF60: // 全角字符
+			width += 2
+		default:
+			width++
+		}
+	}
+	return width
+}
@@ -0,0 +1,127 @@
+package xgit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPinyinOf(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    string
+	}{
+		{"克隆仓库 (ke long) → git clone <url>", "ke long"},
+		{"一行日志 (yi hang ri zhi) → git log --oneline", "yi hang ri zhi"},
+		{"没有拼音 → git status", ""},
+		{"参数 (<url>) 不是拼音", ""},
+	}
+
+	for _, tt := range tests {
+		if result := pinyinOf(tt.description); result != tt.expected {
+			t.Errorf("pinyinOf(%q) = %q，期望 %q", tt.description, result, tt.expected)
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected int
+	}{
+		{"kl", 2},
+		{"仓库操作", 8},
+		{"git命令", 7},
+		{"【复合】", 8},
+	}
+
+	for _, tt := range tests {
+		if result := displayWidth(tt.s); result != tt.expected {
+			t.Errorf("displayWidth(%q) = %d，期望 %d", tt.s, result, tt.expected)
+		}
+	}
+}
+
+func TestMappingRows(t *testing.T) {
+	e := newTestEngine(t)
+	rows := e.mappingRows()
+
+	expectedRows := len(e.commandMap) + len(e.compositeCommands) + len(e.gitCommands)
+	if len(rows) != expectedRows {
+		t.Errorf("映射表应该有 %d 行，得到 %d 行", expectedRows, len(rows))
+	}
+
+	byAlias := make(map[string]mappingRow)
+	for _, row := range rows {
+		byAlias[row.Alias+"/"+row.Category] = row
+	}
+
+	if row := byAlias["kl/仓库操作"]; row.Pinyin != "ke long" || row.Git != "git clone" {
+		t.Errorf("kl 的映射不正确: %+v", row)
+	}
+	if row := byAlias["kstj/复合命令"]; row.Git != "git add . && git commit -m {1} && git push" {
+		t.Errorf("kstj 的映射不正确: %+v", row)
+	}
+	if row := byAlias["stash/"+passthroughCategory]; row.Git != "git stash" {
+		t.Errorf("原生命令 stash 的映射不正确: %+v", row)
+	}
+}
+
+func TestShowMappingList_Formats(t *testing.T) {
+	e := newTestEngine(t)
+	text := captureOutput(e, func() { e.showHelp([]string{"--list"}) })
+	for _, element := range []string{"命令", "拼音", "分类", "git命令", "ke long", "git checkout -b"} {
+		if !strings.Contains(text, element) {
+			t.Errorf("文本映射表中缺少: %s", element)
+		}
+	}
+
+	markdown := captureOutput(e, func() { e.showHelp([]string{"--list", "--format=markdown"}) })
+	if !strings.HasPrefix(markdown, "| 命令 | 拼音 | 分类 | git命令 |\n| --- |") {
+		t.Errorf("Markdown 表头不正确:\n%s", markdown)
+	}
+	if !strings.Contains(markdown, "| `kl` | ke long | 仓库操作 | `git clone` |") {
+		t.Errorf("Markdown 表格中缺少 kl 行:\n%s", markdown)
+	}
+
+	csvOutput := captureOutput(e, func() { e.showHelp([]string{"--list", "--format", "csv"}) })
+	records, err := csv.NewReader(strings.NewReader(csvOutput)).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV 输出无法解析: %v", err)
+	}
+	if len(records) != len(e.mappingRows())+1 || strings.Join(records[0], ",") != "alias,pinyin,category,git" {
+		t.Errorf("CSV 输出的行数或表头不正确: %v", records[0])
+	}
+
+	jsonOutput := captureOutput(e, func() { e.showHelp([]string{"--list", "--format=json"}) })
+	var rows []mappingRow
+	if err := json.Unmarshal([]byte(jsonOutput), &rows); err != nil {
+		t.Fatalf("JSON 输出无法解析: %v", err)
+	}
+	if len(rows) != len(e.mappingRows()) {
+		t.Errorf("JSON 输出的行数不正确，得到 %d", len(rows))
+	}
+}
+
+func TestShowMappingList_UnknownFormat(t *testing.T) {
+	e := newTestEngine(t)
+	var err error
+	output := captureOutput(e, func() { err = e.showHelp([]string{"--list", "--format=xml"}) })
+	if !strings.Contains(output, "不支持的输出格式: xml") || !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("未知格式应该提示错误并返回 ErrInvalidArgs，得到 %v\n%s", err, output)
+	}
+
+	// 脚本可以从退出码发现错误
+	for _, args := range [][]string{{"bz", "--list", "--format=xml"}, {"bz", "--list", "--verbose"}} {
+		var code int
+		captureOutput(e, func() { code = e.Run(args) })
+		if code != 1 {
+			t.Errorf("%v 的退出码应该为 1，得到 %d", args, code)
+		}
+	}
+	if err := e.showHelp([]string{"--list", "--format=csv"}); err != nil {
+		t.Errorf("支持的格式不应该返回错误，得到 %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package xgit
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// 去掉拼音的声调符号，ü 按拼音输入法的习惯写作 v
+var toneMarkReplacer = strings.NewReplacer(
+	"ā", "a", "á", "a", "ǎ", "a", "à", "a",
+	"ē", "e", "é", "e", "ě", "e", "è", "e",
+	"ī", "i", "í", "i", "ǐ", "i", "ì", "i",
+	"ō", "o", "ó", "o", "ǒ", "o", "ò", "o",
+	"ū", "u", "ú", "u", "ǔ", "u", "ù", "u",
+	"ǖ", "v", "ǘ", "v", "ǚ", "v", "ǜ", "v", "ü", "v",
+	"ń", "n", "ň", "n", "ǹ", "n", "ḿ", "m",
+)
+
+// 将命令的其他名称规范化，用于比较：忽略大小写、空白、隔音符号 '、
+// 声调符号和数字声调，如 "Tuī Sòng"、"tui4 song4" 都规范化为 "tuisong"
+func normalizeName(name string) string {
+	name = toneMarkReplacer.Replace(strings.ToLower(name))
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		switch {
+		case unicode.IsSpace(r) || r == '\'' || r == '’':
+			continue
+		case r >= '1' && r <= '5' && i > 0 && isASCIILetter(runes[i-1]) && (i+1 == len(runes) || !unicode.IsDigit(runes[i+1])):
+			// 音节后的数字声调
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isASCIILetter(r rune) bool {
+	return r >= 'a' && r <= 'z'
+}
+
+// 命令在配置中的其他名称
+func (cfg *CommandConfig) commandNames(name string) []string {
+	if cmd, exists := cfg.Commands[name]; exists {
+		return cmd.Names
+	}
+	return cfg.CompositeCommands[name].Names
+}
+
+// 规范化后的其他名称及声明了它的命令（按命令名排序，同一命令只记录一次）
+func nameClaims(cfg *CommandConfig) map[string][]string {
+	claims := make(map[string][]string)
+	claim := func(command string, names []string) {
+		for _, name := range names {
+			key := normalizeName(name)
+			owners := claims[key]
+			if len(owners) == 0 || owners[len(owners)-1] != command {
+				claims[key] = append(owners, command)
+			}
+		}
+	}
+	// 基本命令和复合命令不会同名，统一按命令名排序
+	commands := append(sortedKeys(cfg.Commands), sortedKeys(cfg.CompositeCommands)...)
+	slices.Sort(commands)
+	for _, command := range commands {
+		claim(command, cfg.commandNames(command))
+	}
+	return claims
+}
+
+// 其他名称为什么不能使用，可以使用时返回空字符串
+func (e *Engine) nameConflict(key string, owners []string) string {
+	switch {
+	case key == "":
+		return "为空"
+	case len(owners) > 1:
+		return "同时属于命令 " + strings.Join(owners, "、")
+	case isBuiltinCommand(key):
+		return "与 xgit 自身的命令重名"
+	case e.isGitCommand(key):
+		return "与原生git命令重名"
+	}
+	if _, exists := e.config.Commands[key]; exists {
+		return "与命令 " + key + " 重名"
+	}
+	if _, exists := e.config.CompositeCommands[key]; exists {
+		return "与命令 " + key + " 重名"
+	}
+	return ""
+}
+
+// 注册命令的其他名称，与其他命令冲突的名称被忽略
+func (e *Engine) registerNames() {
+	e.alternateNames = make(map[string]string)
+	for key, owners := range nameClaims(e.config) {
+		if e.nameConflict(key, owners) != "" {
+			continue
+		}
+		e.alternateNames[key] = owners[0]
+	}
+}
+
+// 返回命令的正式名称：name 是其他名称时返回对应的命令，否则原样返回
+func (e *Engine) primaryName(name string) string {
+	if _, exists := e.config.Commands[name]; exists {
+		return name
+	}
+	if _, exists := e.config.CompositeCommands[name]; exists {
+		return name
+	}
+	if command, exists := e.alternateNames[normalizeName(name)]; exists {
+		return command
+	}
+	return name
+}
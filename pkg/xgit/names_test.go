@@ -0,0 +1,145 @@
+package xgit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"tuisong", "tuisong"},
+		{"TuiSong", "tuisong"},
+		{"tui song", "tuisong"},
+		{"tuī sòng", "tuisong"},
+		{"Tuī Sòng", "tuisong"},
+		{"tui1 song4", "tuisong"},
+		{"tui4song4", "tuisong"},
+		{"xi'an", "xian"},
+		{"lǜ", "lv"},
+		{"推送", "推送"},
+		{"推 送", "推送"},
+		{"v2", "v"},
+		{"v12", "v12"},
+	}
+	for _, tt := range tests {
+		if result := normalizeName(tt.name); result != tt.expected {
+			t.Errorf("normalizeName(%q) = %q，期望 %q", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestResolve_AlternateNames(t *testing.T) {
+	e := newTestEngine(t)
+
+	for _, name := range []string{"tuisong", "推送", "tuī sòng", "tui4song4", "TuiSong"} {
+		res, ok := e.Resolve(name)
+		if !ok || res.Name != "ts" || !reflect.DeepEqual(res.Args, []string{"push"}) {
+			t.Errorf("%s 应该解析为 ts，得到 %+v", name, res)
+		}
+	}
+	if res, ok := e.Resolve("快速提交"); !ok || res.Name != "kstj" || res.Kind != KindComposite {
+		t.Errorf("快速提交 应该解析为复合命令 kstj，得到 %+v", res)
+	}
+	if res, ok := e.Resolve("fz"); !ok || res.Name != "ckfz" {
+		t.Errorf("fz 应该解析为 ckfz，得到 %+v", res)
+	}
+}
+
+func TestExecute_AlternateName(t *testing.T) {
+	e := newTestEngine(t)
+	runner := withRecordingRunner(t, e)
+
+	if err := e.Execute("推送", []string{"origin"}); err != nil {
+		t.Fatal(err)
+	}
+	e.DryRun = true
+	output := captureOutput(e, func() { e.Execute("kuaisutijiao", []string{"msg"}) })
+	if !strings.Contains(output, "执行复合命令: kstj") || !strings.Contains(output, "git commit -m msg") {
+		t.Errorf("应该执行复合命令 kstj，实际输出:\n%s", output)
+	}
+	if commands := runner.Commands(); len(commands) == 0 || commands[0] != "git push origin" {
+		t.Errorf("应该执行 git push origin，得到 %v", commands)
+	}
+}
+
+func TestAlternateNames_Conflicts(t *testing.T) {
+	e, err := New(&CommandConfig{
+		Commands: map[string]Command{
+			"ck": {Args: []string{"status"}, Names: []string{"chakan", "zt", "status", "bz"}},
+			"zt": {Args: []string{"status", "-s"}, Names: []string{"cha kan", "zhuangtai"}},
+		},
+		GitCommands: []string{"status"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res, _ := e.Resolve("zt"); res.Name != "zt" {
+		t.Errorf("命令名应该优先于其他名称，得到 %s", res.Name)
+	}
+	if res, _ := e.Resolve("status"); res.Kind != KindGit {
+		t.Errorf("与原生git命令重名的名称应该被忽略，得到 %+v", res)
+	}
+	if _, ok := e.Resolve("chakan"); ok {
+		t.Error("同时属于多个命令的名称不应该解析为任何一个命令")
+	}
+	if res, _ := e.Resolve("zhuangtai"); res.Name != "zt" {
+		t.Errorf("zhuangtai 应该解析为 zt，得到 %s", res.Name)
+	}
+}
+
+func TestShowHelp_AlternateNames(t *testing.T) {
+	e := newTestEngine(t)
+	output := captureOutput(e, func() { e.showHelp([]string{"推送"}) })
+	for _, element := range []string{"命令: ts", "其他名称: tuisong, 推送"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+
+	output = captureOutput(e, func() { e.showHelp([]string{"--git", "tongbufenzhi"}) })
+	if !strings.HasPrefix(output, "tbfz → 复合命令:") {
+		t.Errorf("--git 应该接受其他名称，实际输出:\n%s", output)
+	}
+}
+
+func TestSuggestCommands_AlternateNames(t *testing.T) {
+	e := newTestEngine(t)
+	suggestions := e.suggestCommands("tongbufenzi")
+	if len(suggestions) == 0 || suggestions[0].Name != "tbfz" {
+		t.Errorf("应该根据其他名称建议 tbfz，得到 %v", suggestions)
+	}
+}
+
+func TestCheckConfigSources_AlternateNames(t *testing.T) {
+	issues := checkConfigText(t, `{
+  "commands": {
+    "wd": {"args": ["log"], "names": ["Tuī Sòng", "zt", "status", "bz", "", "wd", "wo de", "WoDe"], "category": "日志操作"}
+  }
+}`)
+
+	assertIssue(t, issues, `test.json:3:39: 错误: 命令 wd 的其他名称 "Tuī Sòng" 同时属于命令 ts、wd，不会生效`)
+	assertIssue(t, issues, `test.json:3:51: 错误: 命令 wd 的其他名称 "zt" 与命令 zt 重名，不会生效`)
+	assertIssue(t, issues, `错误: 命令 wd 的其他名称 "status" 与原生git命令重名，不会生效`)
+	assertIssue(t, issues, `错误: 命令 wd 的其他名称 "bz" 与 xgit 自身的命令重名，不会生效`)
+	assertIssue(t, issues, `错误: 命令 wd 的其他名称 "" 为空，不会生效`)
+	assertIssue(t, issues, `警告: 命令 wd 的其他名称 "wd" 重复`)
+	assertIssue(t, issues, `警告: 命令 wd 的其他名称 "WoDe" 重复`)
+	if len(issues) != 7 {
+		t.Errorf("应该发现 7 个问题，实际:\n%s", strings.Join(issues, "\n"))
+	}
+}
+
+func TestAddAlias_AlternateNameCollision(t *testing.T) {
+	e := newTestEngine(t)
+	isolateUserConfig(t)
+
+	output, err := runAlias(e, "tj", "tuisong", "--", "push", "-f")
+	if err == nil || !strings.Contains(output, "别名 tuisong 已存在: 推送代码 (tui song) → git push") {
+		t.Errorf("与其他名称重名时应该报错，得到 %v\n%s", err, output)
+	}
+}
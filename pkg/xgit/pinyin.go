@@ -0,0 +1,451 @@
+package xgit
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// 拼音字典，覆盖git相关的常用汉字。每行为一个读音及只有这个读音的汉字，
+// 多音字在 polyphones 中列出
+const pinyinTable = `
+a 啊
+ai 爱
+an 安按案
+ban 版办半板
+bang 帮
+bao 包保报
+bei 被备背
+ben 本
+bi 比必笔闭
+bian 变编边
+biao 标表
+bie 别
+bing 并
+bu 不部步布补
+cai 才
+cang 仓
+cao 操草
+ce 测策
+ceng 层
+cha 查插
+chai 拆
+chan 产
+chang 常场
+che 撤车
+cheng 程成称承
+chi 持
+chong 冲
+chu 出初除储处
+chuang 创窗
+ci 次此
+cong 从
+cuo 错
+cun 存
+da 打大
+dai 代待带
+dan 单
+dang 当档
+dao 导到
+de 的
+deng 等
+di 底第递
+dian 点
+ding 定订
+diu 丢
+dong 动
+du 读度
+duan 端短段
+dui 对
+duo 多
+er 二
+fa 发法
+fan 反返
+fang 方放
+fen 分
+feng 封
+fu 复服符附副
+gai 改
+gao 高告
+ge 个格
+gei 给
+gen 根跟
+geng 更
+gong 工共公
+gou 构钩
+gu 固
+guan 关管
+gui 归规
+guo 过
+hao 号
+he 合核
+hou 后
+hu 忽户互
+hua 化
+huan 换环
+hui 回恢
+huo 获
+ji 基记级计机继
+jia 加
+jian 件建检简键拣
+jiang 将
+jiao 交较
+jie 解接结
+jin 进近今
+jing 径境镜
+jiu 旧
+ju 局具
+jue 决
+kai 开
+kan 看
+ke 克可
+kong 空
+ku 库
+kuai 快块
+la 拉
+lian 连
+liang 量两
+lie 列
+lin 临
+ling 令
+liu 留
+long 隆
+lu 录路
+lue 略
+ma 码
+men 们
+mei 每
+mi 密
+ming 名命明
+mo 默
+mu 目
+nei 内
+nian 年
+pai 排
+pei 配
+pi 批
+ping 评
+qi 器弃启期
+qian 签前迁
+qiang 强
+qie 切
+qing 清请情
+qu 取区
+quan 全
+que 确
+ren 认任
+ri 日
+rong 容
+ru 入
+shan 删
+shang 上
+she 设
+shen 深审
+sheng 生
+shi 是时使式示试始史视
+shou 收首
+shu 树输属数
+song 送
+sou 搜
+su 速溯
+suo 所索锁
+tai 态
+ti 提题
+tian 添
+tiao 跳挑
+ting 停
+tong 同统
+tou 头
+tu 图突
+tui 推退
+wai 外
+wan 完
+wei 为位未尾
+wen 文
+wu 无误
+xi 息系
+xia 下
+xian 显
+xiang 详项像向
+xiao 小销
+xie 写
+xin 新信
+xing 型形
+xiu 修
+xu 续需
+xuan 选
+yan 验
+yi 一以已移义异
+ying 应
+yong 用
+you 有游右
+yu 与
+yuan 远原源
+yue 月
+zai 载在
+zan 暂
+ze 责
+zeng 增
+zhan 展
+zhao 找
+zhe 者这
+zheng 整正证
+zhi 支志置值指制止址执只
+zhong 中终
+zhou 周
+zhu 主注助逐
+zhuang 状
+zhui 追
+zi 自子字
+zong 踪
+zu 组
+zui 最
+zuo 作做左
+`
+
+// 多音字的读音，第一个为默认读音
+var polyphones = map[rune][]string{
+	'重': {"zhong", "chong"},
+	'长': {"chang", "zhang"},
+	'行': {"xing", "hang"},
+	'还': {"hai", "huan"},
+	'藏': {"cang", "zang"},
+	'差': {"cha", "chai", "ci"},
+	'调': {"diao", "tiao"},
+	'弹': {"tan", "dan"},
+	'参': {"can", "shen"},
+	'传': {"chuan", "zhuan"},
+	'模': {"mo", "mu"},
+	'校': {"xiao", "jiao"},
+	'地': {"di", "de"},
+	'便': {"bian", "pian"},
+	'和': {"he", "huo"},
+	'了': {"le", "liao"},
+	'得': {"de", "dei"},
+	'着': {"zhe", "zhao", "zhuo"},
+}
+
+// 多音字在词语中的读音，优先于默认读音
+var pinyinPhrases = map[string]string{
+	"重命名": "chong ming ming",
+	"重新":  "chong xin",
+	"重置":  "chong zhi",
+	"重做":  "chong zuo",
+	"重复":  "chong fu",
+	"重试":  "chong shi",
+	"重建":  "chong jian",
+	"重写":  "chong xie",
+	"重放":  "chong fang",
+	"重设":  "chong she",
+	"重启":  "chong qi",
+	"重定向": "chong ding xiang",
+	"重载":  "chong zai",
+	"增长":  "zeng zhang",
+	"成长":  "cheng zhang",
+	"一行":  "yi hang",
+	"单行":  "dan hang",
+	"多行":  "duo hang",
+	"每行":  "mei hang",
+	"换行":  "huan hang",
+	"空行":  "kong hang",
+	"首行":  "shou hang",
+	"逐行":  "zhu hang",
+	"按行":  "an hang",
+	"行号":  "hang hao",
+	"行数":  "hang shu",
+	"行尾":  "hang wei",
+	"命令行": "ming ling hang",
+	"还原":  "huan yuan",
+	"归还":  "gui huan",
+	"调试":  "tiao shi",
+	"调整":  "tiao zheng",
+	"模板":  "mu ban",
+	"校验":  "jiao yan",
+	"校对":  "jiao dui",
+	"了解":  "liao jie",
+}
+
+// 词语的最大长度
+const maxPhraseLength = 3
+
+// 单音字的读音
+var pinyinDictionary = parsePinyinTable(pinyinTable)
+
+func parsePinyinTable(table string) map[rune]string {
+	dictionary := make(map[rune]string)
+	for _, line := range strings.Split(strings.TrimSpace(table), "\n") {
+		reading, chars, _ := strings.Cut(line, " ")
+		for _, char := range chars {
+			dictionary[char] = reading
+		}
+	}
+	return dictionary
+}
+
+// 一个汉字或一段非汉字文本的读音
+type pinyinSyllable struct {
+	Text     string
+	Pinyin   string
+	Readings []string // 多音字的所有读音
+	InPhrase bool     // 读音由所在的词语确定
+}
+
+// 将中文转换为拼音：按词语处理多音字，字母和数字原样保留（转为小写），
+// 空白和标点被忽略。返回字典中没有的汉字
+func toPinyin(text string) (syllables []pinyinSyllable, unknown []rune) {
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.Is(unicode.Han, r):
+			if phrase, n := matchPhrase(runes[i:]); n > 0 {
+				for j, reading := range strings.Fields(phrase) {
+					syllables = append(syllables, pinyinSyllable{Text: string(runes[i+j]), Pinyin: reading, Readings: polyphones[runes[i+j]], InPhrase: true})
+				}
+				i += n
+				continue
+			}
+			if readings, exists := polyphones[r]; exists {
+				syllables = append(syllables, pinyinSyllable{Text: string(r), Pinyin: readings[0], Readings: readings})
+			} else if reading, exists := pinyinDictionary[r]; exists {
+				syllables = append(syllables, pinyinSyllable{Text: string(r), Pinyin: reading})
+			} else {
+				unknown = append(unknown, r)
+			}
+			i++
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			j := i
+			for j < len(runes) && runes[j] < unicode.MaxASCII && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			word := string(runes[i:j])
+			syllables = append(syllables, pinyinSyllable{Text: word, Pinyin: strings.ToLower(word)})
+			i = j
+		default:
+			i++
+		}
+	}
+	return syllables, unknown
+}
+
+// 匹配以 runes 开头的最长词语
+func matchPhrase(runes []rune) (string, int) {
+	for n := min(maxPhraseLength, len(runes)); n >= 2; n-- {
+		if phrase, exists := pinyinPhrases[string(runes[:n])]; exists {
+			return phrase, n
+		}
+	}
+	return "", 0
+}
+
+// 读音是否可能是 Text 的读音：词语中的多音字只有一种读音
+func (s pinyinSyllable) accepts(reading string) bool {
+	if reading == s.Pinyin {
+		return true
+	}
+	if s.InPhrase {
+		return false
+	}
+	for _, r := range s.Readings {
+		if r == reading {
+			return true
+		}
+	}
+	return false
+}
+
+// 以空格分隔的完整拼音
+func joinPinyin(syllables []pinyinSyllable) string {
+	readings := make([]string, len(syllables))
+	for i, s := range syllables {
+		readings[i] = s.Pinyin
+	}
+	return strings.Join(readings, " ")
+}
+
+// 拼音首字母
+func pinyinInitials(readings []string) string {
+	var b strings.Builder
+	for _, reading := range readings {
+		if reading != "" {
+			b.WriteByte(reading[0])
+		}
+	}
+	return b.String()
+}
+
+// 说明中拼音之前的中文名称，如 "推送代码 (tui song) → git push" 中的 "推送代码"
+var descriptionNamePattern = regexp.MustCompile(`^\s*([^(（→]+?)\s*\(`)
+
+// 检查说明中的拼音是否与中文名称的读音一致，如 "重置 (zhong zhi)" 应为 chong zhi。
+// 拼音可以只对应名称中连续的几个字，如 "推送代码 (tui song)"、"查看日志 (ri zhi)"；
+// 名称中有字典之外的字时不检查
+func checkDescriptionPinyin(name, description string) string {
+	declared := pinyinOf(description)
+	m := descriptionNamePattern.FindStringSubmatch(description)
+	if declared == "" || m == nil {
+		return ""
+	}
+	syllables, unknown := toPinyin(m[1])
+	if len(unknown) > 0 {
+		return ""
+	}
+
+	readings := strings.Fields(declared)
+	for start := 0; start+len(readings) <= len(syllables); start++ {
+		matched := true
+		for i, reading := range readings {
+			if !syllables[start+i].accepts(reading) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return ""
+		}
+	}
+	return fmt.Sprintf("命令 %s 说明中的拼音 (%s) 与 %s 的读音 (%s) 不一致", name, declared, m[1], joinPinyin(syllables))
+}
+
+// 根据中文名称生成拼音别名：xgit bm sc-py <中文名称>
+func (e *Engine) generatePinyin(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: xgit bm sc-py <中文名称>")
+	}
+	text := strings.Join(args, " ")
+	syllables, unknown := toPinyin(text)
+	if len(unknown) > 0 {
+		return fmt.Errorf("拼音字典中没有这些字: %s", string(unknown))
+	}
+	if len(syllables) == 0 {
+		return fmt.Errorf("%q 中没有可以转换的文字", text)
+	}
+
+	readings := make([]string, len(syllables))
+	for i, s := range syllables {
+		readings[i] = s.Pinyin
+	}
+	initials := pinyinInitials(readings)
+	full := strings.Join(readings, "")
+
+	fmt.Fprintf(e.Stdout, "名称:   %s\n", text)
+	fmt.Fprintf(e.Stdout, "拼音:   %s\n", strings.Join(readings, " "))
+	fmt.Fprintf(e.Stdout, "首字母: %s\n", initials)
+	fmt.Fprintf(e.Stdout, "全拼:   %s\n", full)
+	for _, s := range syllables {
+		if len(s.Readings) > 1 {
+			fmt.Fprintf(e.Stdout, "多音字: %s 取 %s（可选 %s）\n", s.Text, s.Pinyin, strings.Join(s.Readings, "/"))
+		}
+	}
+	if res, exists := e.Resolve(initials); exists {
+		fmt.Fprintf(e.Stdout, "注意: %s 已被命令 %s 使用\n", initials, res.Name)
+	}
+
+	fmt.Fprintln(e.Stdout)
+	fmt.Fprintln(e.Stdout, "添加别名:")
+	fmt.Fprintf(e.Stdout, "  xgit bm tj %s -d %s -- <git参数...>\n", initials, shellQuote(fmt.Sprintf("%s (%s)", text, strings.Join(readings, " "))))
+	fmt.Fprintln(e.Stdout, "配置中的其他名称:")
+	fmt.Fprintf(e.Stdout, "  \"names\": [%q, %q]\n", full, strings.ReplaceAll(text, " ", ""))
+	return nil
+}
@@ -0,0 +1,152 @@
+package xgit
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestToPinyin(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"查看远程分支", "cha kan yuan cheng fen zhi"},
+		{"重命名分支", "chong ming ming fen zhi"},
+		{"重置到提交", "chong zhi dao ti jiao"},
+		{"重要", ""},
+		{"长格式日志", "chang ge shi ri zhi"},
+		{"按行查看", "an hang cha kan"},
+		{"行为", "xing wei"},
+		{"命令行参数", "ming ling hang can shu"},
+		{"还原文件", "huan yuan wen jian"},
+		{"推送 Tag 到远程", "tui song tag dao yuan cheng"},
+		{"修改 v2 版本", "xiu gai v2 ban ben"},
+	}
+	for _, tt := range tests {
+		syllables, unknown := toPinyin(tt.text)
+		if tt.expected == "" {
+			if len(unknown) == 0 {
+				t.Errorf("%s 中有字典之外的字，应该报告", tt.text)
+			}
+			continue
+		}
+		if len(unknown) > 0 {
+			t.Errorf("%s 中的 %s 不在字典中", tt.text, string(unknown))
+		}
+		if result := joinPinyin(syllables); result != tt.expected {
+			t.Errorf("toPinyin(%q) = %q，期望 %q", tt.text, result, tt.expected)
+		}
+	}
+}
+
+func TestPinyinDictionary_Consistent(t *testing.T) {
+	seen := make(map[rune]string)
+	for _, line := range strings.Split(strings.TrimSpace(pinyinTable), "\n") {
+		reading, chars, _ := strings.Cut(line, " ")
+		for _, char := range chars {
+			if previous, exists := seen[char]; exists {
+				t.Errorf("%c 同时出现在 %s 和 %s 中", char, previous, reading)
+			}
+			if _, exists := polyphones[char]; exists {
+				t.Errorf("多音字 %c 不应该出现在单音字表中", char)
+			}
+			seen[char] = reading
+		}
+	}
+	for phrase, pinyin := range pinyinPhrases {
+		runes := []rune(phrase)
+		readings := strings.Fields(pinyin)
+		if len(runes) != len(readings) || len(runes) > maxPhraseLength {
+			t.Errorf("词语 %s 的拼音 %s 与字数不符", phrase, pinyin)
+			continue
+		}
+		for i, r := range runes {
+			syllables, unknown := toPinyin(string(r))
+			if len(unknown) > 0 || !syllables[0].accepts(readings[i]) {
+				t.Errorf("词语 %s 中 %c 的读音 %s 不在字典中", phrase, r, readings[i])
+			}
+		}
+	}
+}
+
+func TestPinyinDictionary_CoversDefaultConfig(t *testing.T) {
+	e := newTestEngine(t)
+	var missing []rune
+	for name, description := range e.commandHelp {
+		for _, r := range description {
+			if !unicode.Is(unicode.Han, r) {
+				continue
+			}
+			if _, unknown := toPinyin(string(r)); len(unknown) > 0 && !strings.ContainsRune(string(missing), r) {
+				missing = append(missing, r)
+				t.Errorf("命令 %s 说明中的 %c 不在拼音字典中", name, r)
+			}
+		}
+	}
+}
+
+func TestCheckDescriptionPinyin(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		problem     string
+	}{
+		{"ts", "推送代码 (tui song) → git push", ""},
+		{"rz", "查看日志 (ri zhi) → git log", ""},
+		{"cz", "重置 (chong zhi) → git reset", ""},
+		{"cz", "重置 (zhong zhi) → git reset", "命令 cz 说明中的拼音 (zhong zhi) 与 重置 的读音 (chong zhi) 不一致"},
+		{"ah", "按行 (an xing)", "命令 ah 说明中的拼音 (an xing) 与 按行 的读音 (an hang) 不一致"},
+		{"zy", "重要 (zhong yao)", ""},
+		{"ck", "查看状态", ""},
+	}
+	for _, tt := range tests {
+		if problem := checkDescriptionPinyin(tt.name, tt.description); problem != tt.problem {
+			t.Errorf("checkDescriptionPinyin(%q) = %q，期望 %q", tt.description, problem, tt.problem)
+		}
+	}
+}
+
+func TestCheckConfigSources_DescriptionPinyin(t *testing.T) {
+	issues := checkConfigText(t, `{
+  "commands": {
+    "cxjl": {"args": ["reflog"], "description": "重新记录 (zhong xin ji lu)", "category": "日志操作"}
+  }
+}`)
+
+	assertIssue(t, issues, "test.json:3:34: 警告: 命令 cxjl 说明中的拼音 (zhong xin ji lu) 与 重新记录 的读音 (chong xin ji lu) 不一致")
+	if len(issues) != 1 {
+		t.Errorf("应该发现 1 个问题，实际:\n%s", strings.Join(issues, "\n"))
+	}
+}
+
+func TestGeneratePinyin(t *testing.T) {
+	e := newTestEngine(t)
+
+	output, err := runAlias(e, "sc-py", "重命名远程分支")
+	if err != nil {
+		t.Fatalf("生成拼音失败: %v\n%s", err, output)
+	}
+	for _, element := range []string{
+		"拼音:   chong ming ming yuan cheng fen zhi",
+		"首字母: cmmycfz",
+		"全拼:   chongmingmingyuanchengfenzhi",
+		"多音字: 重 取 chong（可选 zhong/chong）",
+		"xgit bm tj cmmycfz -d '重命名远程分支 (chong ming ming yuan cheng fen zhi)' -- <git参数...>",
+		`"names": ["chongmingmingyuanchengfenzhi", "重命名远程分支"]`,
+	} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+
+	output, _ = runAlias(e, "sc-py", "推送")
+	if !strings.Contains(output, "注意: ts 已被命令 ts 使用") {
+		t.Errorf("首字母已被使用时应该提示，实际输出:\n%s", output)
+	}
+
+	output, err = runAlias(e, "sc-py", "重要")
+	if err == nil || !strings.Contains(output, "拼音字典中没有这些字: 要") {
+		t.Errorf("字典之外的字应该报错，得到 %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,76 @@
+package xgit
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// 排序、去重后的名称列表，用二分查找做精确匹配和前缀匹配
+type nameIndex []string
+
+func newNameIndex(groups ...[]string) nameIndex {
+	var names []string
+	for _, group := range groups {
+		names = append(names, group...)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func (idx nameIndex) contains(name string) bool {
+	_, found := slices.BinarySearch(idx, name)
+	return found
+}
+
+// 以 prefix 开头的所有名称，按名称排序
+func (idx nameIndex) withPrefix(prefix string) []string {
+	start := sort.SearchStrings(idx, prefix)
+	end := start
+	for end < len(idx) && strings.HasPrefix(idx[end], prefix) {
+		end++
+	}
+	return idx[start:end:end]
+}
+
+// AmbiguousCommandError 表示命令缩写对应多个命令
+type AmbiguousCommandError struct {
+	Prefix     string
+	Candidates []string
+}
+
+func (e *AmbiguousCommandError) Error() string {
+	return fmt.Sprintf("命令 %s 有歧义，可能是: %s", e.Prefix, strings.Join(e.Candidates, ", "))
+}
+
+// 将命令缩写展开为以它开头的唯一命令（基本命令、复合命令或原生git命令），
+// 如 cj → cjfz。没有以它开头的命令时返回空字符串，有多个时返回 *AmbiguousCommandError
+func (e *Engine) completePrefix(prefix string) (string, error) {
+	if prefix == "" {
+		return "", nil
+	}
+	candidates := e.commandIndex.withPrefix(prefix)
+	switch len(candidates) {
+	case 0:
+		return "", nil
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", &AmbiguousCommandError{Prefix: prefix, Candidates: slices.Clone(candidates)}
+	}
+}
+
+// 报告有歧义的命令缩写，列出所有候选命令
+func (e *Engine) reportAmbiguousCommand(err *AmbiguousCommandError) {
+	fmt.Fprintf(e.Stdout, "命令 %s 有歧义，可能是:\n", err.Prefix)
+	for _, name := range err.Candidates {
+		help, exists := e.commandHelp[name]
+		if !exists {
+			help = "→ git " + name
+		}
+		fmt.Fprintf(e.Stdout, "  %-6s %s\n", name, help)
+	}
+	fmt.Fprintln(e.Stdout)
+	fmt.Fprintln(e.Stdout, "请输入更长的命令名")
+}
@@ -0,0 +1,97 @@
+package xgit
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNameIndex(t *testing.T) {
+	idx := newNameIndex([]string{"cjfz", "ckfz", "status"}, []string{"stash", "cjbq", "status"})
+
+	if !reflect.DeepEqual(idx, nameIndex{"cjbq", "cjfz", "ckfz", "stash", "status"}) {
+		t.Errorf("索引应该排序并去重，得到 %v", idx)
+	}
+	if !idx.contains("ckfz") || idx.contains("ck") {
+		t.Error("contains 应该只匹配完整名称")
+	}
+
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"cj", []string{"cjbq", "cjfz"}},
+		{"ck", []string{"ckfz"}},
+		{"st", []string{"stash", "status"}},
+		{"statu", []string{"status"}},
+		{"x", nil},
+		{"", []string{"cjbq", "cjfz", "ckfz", "stash", "status"}},
+	}
+	for _, tt := range tests {
+		if result := idx.withPrefix(tt.prefix); !slices.Equal(result, tt.expected) {
+			t.Errorf("withPrefix(%q) = %v，期望 %v", tt.prefix, result, tt.expected)
+		}
+	}
+}
+
+func TestExecute_UniquePrefix(t *testing.T) {
+	e := newTestEngine(t)
+	runner := withRecordingRunner(t, e)
+
+	for _, command := range []string{"yhr", "statu"} {
+		if err := e.Execute(command, nil); err != nil {
+			t.Fatalf("%s 应该按唯一前缀执行: %v", command, err)
+		}
+	}
+	expected := []string{"git log --oneline", "git status"}
+	if commands := runner.Commands(); !reflect.DeepEqual(commands, expected) {
+		t.Errorf("应该执行 %v，得到 %v", expected, commands)
+	}
+}
+
+func TestExecute_AmbiguousPrefix(t *testing.T) {
+	e := newTestEngine(t)
+	runner := withRecordingRunner(t, e)
+
+	var err error
+	output := captureOutput(e, func() { err = e.Execute("cj", []string{"feature"}) })
+
+	var ambiguous *AmbiguousCommandError
+	if !errors.As(err, &ambiguous) || !reflect.DeepEqual(ambiguous.Candidates, []string{"cjbq", "cjfz"}) {
+		t.Fatalf("有歧义的缩写应该返回 AmbiguousCommandError，得到 %v", err)
+	}
+	for _, element := range []string{"命令 cj 有歧义，可能是:", "cjbq   创建标签", "cjfz   创建分支"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+	if commands := runner.Commands(); len(commands) > 0 {
+		t.Errorf("有歧义时不应该执行任何命令，得到 %v", commands)
+	}
+}
+
+func TestExecute_ExactNameBeforePrefix(t *testing.T) {
+	e, err := New(&CommandConfig{
+		Commands: map[string]Command{
+			"cj":   {Args: []string{"cherry-pick"}},
+			"cjfz": {Args: []string{"checkout", "-b"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := withRecordingRunner(t, e)
+
+	if err := e.Execute("cj", []string{"abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Execute("cjf", []string{"dev"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"git cherry-pick abc", "git checkout -b dev"}
+	if commands := runner.Commands(); !reflect.DeepEqual(commands, expected) {
+		t.Errorf("完整的命令名应该优先于前缀匹配，期望 %v，得到 %v", expected, commands)
+	}
+}
@@ -0,0 +1,135 @@
+package xgit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// 一次git调用
+type GitCommand struct {
+	Args   []string
+	Dir    string    // 工作目录，为空时使用当前目录
+	Env    []string  // 追加到当前进程环境变量之后的 KEY=VALUE
+	Stdin  io.Reader // 为 nil 时不提供输入
+	Stdout io.Writer // 为 nil 时捕获到 GitResult.Output
+	Stderr io.Writer // 为 nil 时捕获到 GitResult.Stderr
+}
+
+// git调用的结果
+type GitResult struct {
+	ExitCode int
+	Output   string // 捕获的标准输出
+	Stderr   string // 捕获的标准错误
+}
+
+// git以非零退出码结束
+type GitExitError struct {
+	Args     []string
+	ExitCode int
+	Stderr   string
+}
+
+func (e *GitExitError) Error() string {
+	return fmt.Sprintf("%s 退出码 %d", formatGitCommand(e.Args), e.ExitCode)
+}
+
+// 执行git命令的方式。退出码非零时返回 *GitExitError，
+// 无法启动git时返回其他错误
+type GitRunner interface {
+	Run(cmd GitCommand) (GitResult, error)
+}
+
+// 调用系统中的git
+type ExecRunner struct{}
+
+func (ExecRunner) Run(cmd GitCommand) (GitResult, error) {
+	c := exec.Command("git", cmd.Args...)
+	c.Dir = cmd.Dir
+	if len(cmd.Env) > 0 {
+		c.Env = append(os.Environ(), cmd.Env...)
+	}
+	c.Stdin = cmd.Stdin
+
+	var stdout, stderr bytes.Buffer
+	c.Stdout, c.Stderr = cmd.Stdout, cmd.Stderr
+	if c.Stdout == nil {
+		c.Stdout = &stdout
+	}
+	if c.Stderr == nil {
+		c.Stderr = &stderr
+	}
+
+	err := c.Run()
+	result := GitResult{Output: stdout.String(), Stderr: stderr.String()}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitCode()
+		return result, &GitExitError{Args: cmd.Args, ExitCode: result.ExitCode, Stderr: result.Stderr}
+	}
+	return result, err
+}
+
+// 记录所有调用而不执行git的 GitRunner，用于测试
+type RecordingRunner struct {
+	Calls []GitCommand
+	stubs []gitStub
+}
+
+// 预设的结果，按参数前缀匹配
+type gitStub struct {
+	prefix []string
+	result GitResult
+}
+
+// 为以 prefix 开头的git调用预设结果，后预设的优先；未预设的调用成功且没有输出
+func (r *RecordingRunner) Stub(result GitResult, prefix ...string) {
+	r.stubs = append(r.stubs, gitStub{prefix: prefix, result: result})
+}
+
+func (r *RecordingRunner) Run(cmd GitCommand) (GitResult, error) {
+	r.Calls = append(r.Calls, cmd)
+
+	var result GitResult
+	for i := len(r.stubs) - 1; i >= 0; i-- {
+		if hasArgsPrefix(cmd.Args, r.stubs[i].prefix) {
+			result = r.stubs[i].result
+			break
+		}
+	}
+
+	if cmd.Stdout != nil {
+		io.WriteString(cmd.Stdout, result.Output)
+	}
+	if cmd.Stderr != nil {
+		io.WriteString(cmd.Stderr, result.Stderr)
+	}
+	if result.ExitCode != 0 {
+		return result, &GitExitError{Args: cmd.Args, ExitCode: result.ExitCode, Stderr: result.Stderr}
+	}
+	return result, nil
+}
+
+// 记录的调用，格式化为命令行
+func (r *RecordingRunner) Commands() []string {
+	commands := make([]string, len(r.Calls))
+	for i, call := range r.Calls {
+		commands[i] = formatGitCommand(call.Args)
+	}
+	return commands
+}
+
+func hasArgsPrefix(args, prefix []string) bool {
+	if len(prefix) > len(args) {
+		return false
+	}
+	for i, p := range prefix {
+		if args[i] != p {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+package xgit
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 用 RecordingRunner 代替真实的git
+func withRecordingRunner(t *testing.T, e *Engine) *RecordingRunner {
+	t.Helper()
+	runner := &RecordingRunner{}
+	e.Runner = runner
+	return runner
+}
+
+func TestExecRunner(t *testing.T) {
+	isolateGit(t)
+	dir := t.TempDir()
+
+	_, err := ExecRunner{}.Run(GitCommand{Args: []string{"init", "-q", "-b", "main"}, Dir: dir})
+	if err != nil {
+		t.Fatalf("git init 失败: %v", err)
+	}
+
+	result, err := ExecRunner{}.Run(GitCommand{
+		Args: []string{"config", "user.name"},
+		Dir:  dir,
+		Env:  []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=user.name", "GIT_CONFIG_VALUE_0=测试"},
+	})
+	if err != nil || strings.TrimSpace(result.Output) != "测试" {
+		t.Errorf("应该捕获输出并传入环境变量，得到 %q (%v)", result.Output, err)
+	}
+
+	result, err = ExecRunner{}.Run(GitCommand{Args: []string{"rev-parse", "--verify", "nonexistent"}, Dir: dir})
+	var exitErr *GitExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 128 || result.ExitCode != 128 {
+		t.Errorf("失败时应该返回退出码 128 的 *GitExitError，得到 %v", err)
+	}
+	if !strings.Contains(exitErr.Stderr, "fatal:") {
+		t.Errorf("应该捕获标准错误，得到 %q", exitErr.Stderr)
+	}
+}
+
+func TestRecordingRunner(t *testing.T) {
+	runner := &RecordingRunner{}
+	runner.Stub(GitResult{Output: "main\n"}, "symbolic-ref")
+	runner.Stub(GitResult{ExitCode: 1, Stderr: "失败"}, "diff")
+	runner.Stub(GitResult{}, "diff", "--cached")
+
+	var out strings.Builder
+	if _, err := runner.Run(GitCommand{Args: []string{"symbolic-ref", "--short", "HEAD"}, Stdout: &out}); err != nil || out.String() != "main\n" {
+		t.Errorf("应该返回预设的输出，得到 %q (%v)", out.String(), err)
+	}
+
+	_, err := runner.Run(GitCommand{Args: []string{"diff", "--quiet"}})
+	var exitErr *GitExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode != 1 || exitErr.Stderr != "失败" {
+		t.Errorf("应该返回预设的失败结果，得到 %v", err)
+	}
+	if _, err := runner.Run(GitCommand{Args: []string{"diff", "--cached", "--quiet"}}); err != nil {
+		t.Errorf("后预设的结果应该优先，得到 %v", err)
+	}
+	if _, err := runner.Run(GitCommand{Args: []string{"push"}}); err != nil {
+		t.Errorf("未预设的调用应该成功，得到 %v", err)
+	}
+
+	expected := []string{"git symbolic-ref --short HEAD", "git diff --quiet", "git diff --cached --quiet", "git push"}
+	if !reflect.DeepEqual(runner.Commands(), expected) {
+		t.Errorf("记录的调用不正确，得到 %v", runner.Commands())
+	}
+}
+
+func TestHandlePinyinCommand_Recorded(t *testing.T) {
+	e := newTestEngine(t)
+	tests := []struct {
+		command  string
+		args     []string
+		expected string
+	}{
+		{"ts", nil, "git push"},
+		{"cjfz", []string{"feature"}, "git checkout -b feature"},
+		{"tj", []string{"-m", "修复 登录"}, "git commit -m '修复 登录'"},
+		{"status", []string{"-s"}, "git status -s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			runner := withRecordingRunner(t, e)
+			if err := e.Execute(tt.command, tt.args); err != nil {
+				t.Fatalf("执行失败: %v", err)
+			}
+			if got := runner.Commands(); len(got) != 1 || got[0] != tt.expected {
+				t.Errorf("期望执行 %s，得到 %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHandlePinyinCommand_RecordedFailure(t *testing.T) {
+	e := newTestEngine(t)
+	runner := withRecordingRunner(t, e)
+	runner.Stub(GitResult{ExitCode: 128}, "push")
+
+	err := e.Execute("ts", nil)
+	if ExitCode(err) != 128 {
+		t.Errorf("应该沿用git的退出码 128，得到 %d (%v)", ExitCode(err), err)
+	}
+}
+
+func TestCompositeCommand_Recorded(t *testing.T) {
+	e := newTestEngine(t)
+	runner := withRecordingRunner(t, e)
+	runner.Stub(GitResult{Output: "main\n"}, "symbolic-ref")
+	runner.Stub(GitResult{ExitCode: 128}, "ls-remote")
+	runner.Stub(GitResult{ExitCode: 2}, "remote", "get-url")
+
+	var err error
+	output := captureOutput(e, func() {
+		err = e.executeCompositeCommand("ycsh", e.compositeCommands["ycsh"], []string{"--no-check", "https://example.com/repo.git"})
+	})
+	if err != nil {
+		t.Fatalf("设置远程仓库失败: %v\n%s", err, output)
+	}
+
+	var executed []string
+	for _, command := range runner.Commands() {
+		if strings.HasPrefix(command, "git remote add") || strings.HasPrefix(command, "git push") {
+			executed = append(executed, command)
+		}
+	}
+	expected := []string{"git remote add origin https://example.com/repo.git", "git push -u origin main"}
+	if !reflect.DeepEqual(executed, expected) {
+		t.Errorf("执行的命令不正确，得到 %v\n全部调用: %v", executed, runner.Commands())
+	}
+}
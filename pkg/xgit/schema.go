@@ -0,0 +1,160 @@
+package xgit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// 生成的 JSON Schema 遵循的草案版本
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// 配置结构体及其字段的说明，键为 "类型名" 或 "类型名.字段名"
+var schemaDescriptions = map[string]string{
+	"CommandConfig":                   "xgit 命令配置，后加载的配置文件会添加或覆盖前面配置中的同名命令",
+	"CommandConfig.Schema":            "JSON Schema 的地址，供编辑器补全和校验，xgit 会忽略它",
+	"CommandConfig.Settings":          "行为设置",
+	"CommandConfig.Categories":        "命令分类及其显示顺序",
+	"CommandConfig.Commands":          "基本命令：拼音别名到一条git命令的映射",
+	"CommandConfig.CompositeCommands": "复合命令：按顺序执行多条git命令",
+	"CommandConfig.GitCommands":       "可以直接执行的原生git命令",
+	"Command":                         "映射为一条git命令的拼音命令",
+	"Command.Args":                    "git参数，用户参数会追加在后面",
+	"Command.Description":             "帮助中显示的说明，括号中的拼音用于命令纠错，如 \"克隆仓库 (ke long) → git clone <url>\"",
+	"Command.Names":                   "命令的其他名称，如全拼 \"tuisong\" 或汉字 \"推送\"，比较时忽略大小写、空格和声调",
+	"Command.Category":                "所属分类",
+	"Command.Order":                   "分类内的排序权重，从小到大排列",
+	"Command.Examples":                "用法示例",
+	"CompositeCommand":                "由多个步骤组成的复合命令",
+	"CompositeCommand.Steps":          "依次执行的git命令",
+	"CompositeCommand.Params":         "参数名，用于用法说明和缺少参数时的提示",
+	"CompositeCommand.Usage":          "用法说明，未设置时根据 params 生成",
+	"CompositeCommand.Handler":        "内置的处理器，设置后由处理器执行命令，steps 只用于显示",
+	"CompositeCommand.Description":    "帮助中显示的说明",
+	"CompositeCommand.Names":          "命令的其他名称，如全拼或汉字",
+	"CompositeCommand.Category":       "所属分类",
+	"CompositeCommand.Order":          "分类内的排序权重，从小到大排列",
+	"CompositeCommand.Examples":       "用法示例",
+	"Example":                         "命令的用法示例",
+	"Example.Command":                 "完整的命令行，以 xgit 开头",
+	"Example.Description":             "示例的说明",
+	"Category":                        "命令分类",
+	"Category.Name":                   "分类名",
+	"Category.Order":                  "排序权重，从小到大排列",
+	"Settings":                        "行为设置",
+	"Settings.Autocorrect":            "未知命令的自动纠正：0 只给出建议，负数立即执行，正数为执行前等待的时间（单位 0.1 秒）",
+	"Settings.ConfirmDestructive":     "执行 reset --hard、push -f 等破坏性操作前是否需要确认，默认需要",
+}
+
+// 必填的字段
+var schemaRequired = map[string]bool{
+	"Command.Args":           true,
+	"CompositeCommand.Steps": true,
+	"Example.Command":        true,
+	"Category.Name":          true,
+}
+
+// 复合命令步骤中每个参数的说明
+const stepArgDescription = "git参数，可以使用占位符：{1} {2} ... 第 N 个参数（必填），{N:默认值} 第 N 个参数（可选），{args} 其余参数（单独作为一个参数），{branch} 当前分支名"
+
+// 生成描述配置文件格式的 JSON Schema
+func configSchema() map[string]any {
+	defs := make(map[string]any)
+	schema := schemaObject(reflect.TypeOf(CommandConfig{}), defs)
+	schema["$schema"] = schemaDraft
+	schema["title"] = "xgit 命令配置"
+	schema["$defs"] = defs
+	return schema
+}
+
+// 类型对应的 schema，结构体放入 defs 并返回引用
+func schemaFor(t reflect.Type, defs map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaFor(t.Elem(), defs)
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), defs)}
+	case reflect.Struct:
+		if _, exists := defs[t.Name()]; !exists {
+			defs[t.Name()] = nil
+			defs[t.Name()] = schemaObject(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	}
+	panic(fmt.Sprintf("无法为类型 %s 生成 schema", t))
+}
+
+// 结构体对应的 schema，属性名取自 json 标签
+func schemaObject(t reflect.Type, defs map[string]any) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := t.Name() + "." + field.Name
+		property := schemaFor(field.Type, defs)
+		if description := schemaDescriptions[key]; description != "" {
+			property["description"] = description
+		}
+		refineSchema(key, property)
+		properties[name] = property
+		if schemaRequired[key] {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if description := schemaDescriptions[t.Name()]; description != "" {
+		schema["description"] = description
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// 补充无法从类型得到的约束
+func refineSchema(key string, property map[string]any) {
+	switch key {
+	case "Command.Args":
+		property["minItems"] = 1
+	case "CompositeCommand.Steps":
+		property["minItems"] = 1
+		step := property["items"].(map[string]any)
+		step["minItems"] = 1
+		step["items"].(map[string]any)["description"] = stepArgDescription
+	case "CompositeCommand.Handler":
+		handlers := make([]string, 0, len(compositeHandlers))
+		for name := range compositeHandlers {
+			handlers = append(handlers, name)
+		}
+		sort.Strings(handlers)
+		property["enum"] = handlers
+	}
+}
+
+// 输出配置文件的 JSON Schema
+func (e *Engine) showSchema() error {
+	encoder := json.NewEncoder(e.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(configSchema())
+}
@@ -0,0 +1,159 @@
+package xgit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// 用 schema 中用到的关键字检查一个JSON值，返回不符合的地方
+func schemaViolations(schema, defs map[string]any, value any, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return schemaViolations(defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any), defs, value, path)
+	}
+
+	var problems []string
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		problems = append(problems, fmt.Sprintf("%s: %v 不在 %v 中", path, value, enum))
+	}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return append(problems, path+": 应该是对象")
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, exists := object[name.(string)]; !exists {
+				problems = append(problems, fmt.Sprintf("%s: 缺少 %s", path, name))
+			}
+		}
+		for key, child := range object {
+			if property, exists := properties[key]; exists {
+				problems = append(problems, schemaViolations(property.(map[string]any), defs, child, path+"/"+key)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]any); ok {
+				problems = append(problems, schemaViolations(additional, defs, child, path+"/"+key)...)
+			} else if schema["additionalProperties"] == false {
+				problems = append(problems, fmt.Sprintf("%s: 不允许的属性 %s", path, key))
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return append(problems, path+": 应该是数组")
+		}
+		if minItems, ok := schema["minItems"].(float64); ok && len(array) < int(minItems) {
+			problems = append(problems, fmt.Sprintf("%s: 至少需要 %v 项", path, minItems))
+		}
+		for i, item := range array {
+			problems = append(problems, schemaViolations(schema["items"].(map[string]any), defs, item, fmt.Sprintf("%s/%d", path, i))...)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			problems = append(problems, path+": 应该是字符串")
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int(n)) {
+			problems = append(problems, path+": 应该是整数")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			problems = append(problems, path+": 应该是布尔值")
+		}
+	}
+	return problems
+}
+
+// 经过JSON往返的 schema，与编辑器读到的内容一致
+func loadedSchema(t *testing.T) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(configSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestConfigSchema_UpToDate(t *testing.T) {
+	e := newTestEngine(t)
+	var err error
+	output := captureOutput(e, func() { err = e.configCommand([]string{"schema"}) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	committed, err := os.ReadFile("commands.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(committed, []byte(output)) {
+		t.Error("commands.schema.json 与生成的 schema 不一致，运行 make schema 重新生成")
+	}
+}
+
+func TestConfigSchema_DefaultConfig(t *testing.T) {
+	schema := loadedSchema(t)
+	var config map[string]any
+	if err := json.Unmarshal(defaultConfigData, &config); err != nil {
+		t.Fatal(err)
+	}
+	for _, problem := range schemaViolations(schema, schema["$defs"].(map[string]any), config, "") {
+		t.Errorf("默认配置不符合 schema: %s", problem)
+	}
+}
+
+func TestConfigSchema_RejectsMistakes(t *testing.T) {
+	schema := loadedSchema(t)
+	var config map[string]any
+	err := json.Unmarshal([]byte(`{
+		"commands": {"ck": {"arg": ["status"]}},
+		"composite_commands": {"tb": {"steps": [[]], "handler": "no-such-handler"}},
+		"settings": {"autocorrect": "yes"}
+	}`), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems := strings.Join(schemaViolations(schema, schema["$defs"].(map[string]any), config, ""), "\n")
+	for _, want := range []string{
+		"/commands/ck: 缺少 args",
+		"/commands/ck: 不允许的属性 arg",
+		"/composite_commands/tb/steps/0: 至少需要 1 项",
+		"/composite_commands/tb/handler: no-such-handler 不在",
+		"/settings/autocorrect: 应该是整数",
+	} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("应该发现 %q，实际:\n%s", want, problems)
+		}
+	}
+}
+
+func TestConfigSchema_Handlers(t *testing.T) {
+	defs := configSchema()["$defs"].(map[string]any)
+	handler := defs["CompositeCommand"].(map[string]any)["properties"].(map[string]any)["handler"].(map[string]any)
+	for name := range compositeHandlers {
+		if !slices.Contains(handler["enum"].([]string), name) {
+			t.Errorf("handler 的取值中缺少 %s", name)
+		}
+	}
+}
+
+func TestParseConfig_SchemaKey(t *testing.T) {
+	cfg, err := parseConfig([]byte(`{"$schema": "./commands.schema.json", "commands": {"ck": {"args": ["status"]}}}`), "test.json")
+	if err != nil {
+		t.Fatalf("带有 $schema 的配置应该能解析，得到 %v", err)
+	}
+	if cfg.Schema != "./commands.schema.json" || len(cfg.Commands["ck"].Args) != 1 {
+		t.Errorf("解析结果不正确: %+v", cfg)
+	}
+}
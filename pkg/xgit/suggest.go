@@ -0,0 +1,146 @@
+package xgit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 最多显示的建议数量
+const maxSuggestions = 5
+
+// 与未知命令相近的候选命令
+type suggestion struct {
+	Name     string
+	Distance int
+}
+
+// 为未知命令寻找相近的命令：比较命令名本身、其他名称和说明中的完整拼音，
+// 因此 cjz 能匹配 cjfz，tuisogn 能匹配 ts
+func (e *Engine) suggestCommands(input string) []suggestion {
+	input = strings.ToLower(input)
+	limit := suggestionThreshold(input)
+
+	best := make(map[string]int)
+	consider := func(name, target string) {
+		if target == "" {
+			return
+		}
+		d := editDistance(input, target)
+		if d > limit {
+			return
+		}
+		if old, exists := best[name]; !exists || d < old {
+			best[name] = d
+		}
+	}
+
+	for name := range e.commandMap {
+		consider(name, name)
+		consider(name, strings.ReplaceAll(pinyinOf(e.commandHelp[name]), " ", ""))
+	}
+	for name := range e.compositeCommands {
+		consider(name, name)
+		consider(name, strings.ReplaceAll(pinyinOf(e.commandHelp[name]), " ", ""))
+	}
+	for key, name := range e.alternateNames {
+		consider(name, key)
+	}
+	for _, name := range e.gitCommands {
+		consider(name, name)
+	}
+
+	suggestions := make([]suggestion, 0, len(best))
+	for name, d := range best {
+		suggestions = append(suggestions, suggestion{Name: name, Distance: d})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Distance != suggestions[j].Distance {
+			return suggestions[i].Distance < suggestions[j].Distance
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// 允许的最大编辑距离，输入越长容忍的错误越多
+func suggestionThreshold(input string) int {
+	n := len([]rune(input))
+	switch {
+	case n <= 2:
+		return 1
+	case n <= 6:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// 计算两个字符串的编辑距离，相邻字符交换算作一次编辑
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+// 报告未知命令并给出建议。启用自动纠正且只有一个最佳候选时，
+// 在倒计时结束后返回该候选，由调用方继续执行
+func (e *Engine) reportUnknownCommand(command string) (string, bool) {
+	suggestions := e.suggestCommands(command)
+
+	if len(suggestions) > 0 && e.config.Settings.Autocorrect != nil && *e.config.Settings.Autocorrect != 0 {
+		unique := len(suggestions) == 1 || suggestions[0].Distance < suggestions[1].Distance
+		if unique {
+			target := suggestions[0].Name
+			delay := *e.config.Settings.Autocorrect
+			fmt.Fprintf(e.Stdout, "警告: 命令 '%s' 不存在。\n", command)
+			if delay < 0 {
+				fmt.Fprintf(e.Stdout, "假定您要运行的是 '%s'，立即执行。\n", target)
+			} else {
+				fmt.Fprintf(e.Stdout, "假定您要运行的是 '%s'，将在 %.1f 秒后执行（按 Ctrl+C 取消）。\n", target, float64(delay)/10)
+				e.Sleep(time.Duration(delay) * 100 * time.Millisecond)
+			}
+			return target, true
+		}
+	}
+
+	fmt.Fprintf(e.Stdout, "未知命令: %s\n", command)
+	if len(suggestions) > 0 {
+		fmt.Fprintln(e.Stdout)
+		fmt.Fprintln(e.Stdout, "您是不是想要运行:")
+		for _, s := range suggestions {
+			help, exists := e.commandHelp[s.Name]
+			if !exists {
+				help = "→ git " + s.Name
+			}
+			fmt.Fprintf(e.Stdout, "  %-6s %s\n", s.Name, help)
+		}
+		fmt.Fprintln(e.Stdout)
+	}
+	fmt.Fprintln(e.Stdout, "运行 'xgit bz' 查看所有可用命令")
+	return "", false
+}
@@ -0,0 +1,143 @@
+package xgit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"ts", "ts", 0},
+		{"cjz", "cjfz", 1},
+		{"cjz", "ckfz", 2},
+		{"stauts", "status", 1},
+		{"推送", "推送代码", 2},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		if result := editDistance(tt.a, tt.b); result != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d，期望 %d", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+// 检查建议列表中是否包含指定命令
+func hasSuggestion(suggestions []suggestion, name string) bool {
+	for _, s := range suggestions {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSuggestCommands(t *testing.T) {
+	e := newTestEngine(t)
+	tests := []struct {
+		input    string
+		first    string
+		contains []string
+	}{
+		{"cjz", "cjfz", []string{"ckfz"}},
+		{"tuisong", "ts", nil},
+		{"tuison", "ts", nil},
+		{"stauts", "status", nil},
+		{"yhr", "yhrz", nil},
+	}
+
+	for _, tt := range tests {
+		suggestions := e.suggestCommands(tt.input)
+		if len(suggestions) == 0 || suggestions[0].Name != tt.first {
+			t.Errorf("suggestCommands(%s) 的首选应该是 %s，得到 %v", tt.input, tt.first, suggestions)
+			continue
+		}
+		for _, name := range tt.contains {
+			if !hasSuggestion(suggestions, name) {
+				t.Errorf("suggestCommands(%s) 应该包含 %s，得到 %v", tt.input, name, suggestions)
+			}
+		}
+		if len(suggestions) > maxSuggestions {
+			t.Errorf("建议数量不应超过 %d，得到 %d", maxSuggestions, len(suggestions))
+		}
+	}
+
+	if suggestions := e.suggestCommands("xyzxyzxyz"); len(suggestions) != 0 {
+		t.Errorf("完全不相关的输入不应有建议，得到 %v", suggestions)
+	}
+}
+
+// 临时修改自动纠正设置
+func withAutocorrect(t *testing.T, e *Engine, value *int) {
+	t.Helper()
+	e.config.Settings.Autocorrect = value
+}
+
+func TestReportUnknownCommand_Suggestions(t *testing.T) {
+	e := newTestEngine(t)
+	withAutocorrect(t, e, nil)
+
+	var target string
+	var ok bool
+	output := captureOutput(e, func() {
+		target, ok = e.reportUnknownCommand("cjz")
+	})
+
+	if ok || target != "" {
+		t.Errorf("未启用自动纠正时不应返回候选命令，得到 %s", target)
+	}
+	for _, element := range []string{"未知命令: cjz", "您是不是想要运行:", "cjfz", "ckfz", "运行 'xgit bz' 查看所有可用命令"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+}
+
+func TestReportUnknownCommand_Autocorrect(t *testing.T) {
+	e := newTestEngine(t)
+	delay := 15
+	withAutocorrect(t, e, &delay)
+
+	var slept time.Duration
+	e.Sleep = func(d time.Duration) { slept = d }
+
+	var target string
+	var ok bool
+	output := captureOutput(e, func() {
+		target, ok = e.reportUnknownCommand("tuisong")
+	})
+
+	if !ok || target != "ts" {
+		t.Errorf("应该自动纠正为 ts，得到 %s, %v", target, ok)
+	}
+	if slept != 1500*time.Millisecond {
+		t.Errorf("应该等待 1.5 秒，实际等待 %v", slept)
+	}
+	if !strings.Contains(output, "假定您要运行的是 'ts'，将在 1.5 秒后执行") {
+		t.Errorf("应该提示即将执行的命令，实际输出:\n%s", output)
+	}
+}
+
+func TestReportUnknownCommand_AutocorrectAmbiguous(t *testing.T) {
+	e := newTestEngine(t)
+	immediate := -1
+	withAutocorrect(t, e, &immediate)
+
+	// tj 和 ts 与 tx 的距离相同，不应自动执行
+	var ok bool
+	output := captureOutput(e, func() {
+		_, ok = e.reportUnknownCommand("tx")
+	})
+
+	if ok {
+		t.Errorf("有多个同样相近的候选时不应自动纠正，实际输出:\n%s", output)
+	}
+	if !strings.Contains(output, "您是不是想要运行:") {
+		t.Errorf("不自动纠正时应该列出建议，实际输出:\n%s", output)
+	}
+}
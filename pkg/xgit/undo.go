@@ -0,0 +1,277 @@
+package xgit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 恢复到某个仓库状态需要执行的git命令，以及向用户展示的变化
+type restorePlan struct {
+	Steps   [][]string
+	Changes []string
+}
+
+func (p *restorePlan) add(step []string, change string) {
+	p.Steps = append(p.Steps, step)
+	p.Changes = append(p.Changes, change)
+}
+
+// 提交的短哈希
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// 计算从 current 恢复到 target 的步骤：先回到原来的分支并重置它，
+// 再恢复其他分支，最后恢复储藏
+func (e *Engine) planRestore(current, target repoState) restorePlan {
+	var plan restorePlan
+
+	// 1. 回到操作前所在的分支
+	tip := current.Head
+	if target.Branch != current.Branch {
+		switch {
+		case target.Branch == "":
+			if target.Head != "" {
+				plan.add([]string{"checkout", "--detach", target.Head}, fmt.Sprintf("切换到分离头指针 %s", shortSHA(target.Head)))
+			}
+			tip = target.Head
+		case current.Branches[target.Branch] != "":
+			plan.add([]string{"checkout", target.Branch}, fmt.Sprintf("切换回分支 %s", target.Branch))
+			tip = current.Branches[target.Branch]
+		default:
+			plan.add([]string{"checkout", "-b", target.Branch, target.Head}, fmt.Sprintf("重新创建并切换到分支 %s (%s)", target.Branch, shortSHA(target.Head)))
+			tip = target.Head
+		}
+	}
+
+	// 2. 将当前分支重置到操作前的提交，--keep 会保留与之不冲突的本地修改
+	if target.Head != "" && tip != target.Head {
+		name := target.Branch
+		if name == "" {
+			name = "HEAD"
+		}
+		plan.add([]string{"reset", "--keep", target.Head}, fmt.Sprintf("分支 %s: %s → %s", name, shortSHA(tip), shortSHA(target.Head)))
+		if commits, err := e.gitOutput("log", "--oneline", target.Head+".."+tip); err == nil {
+			for _, line := range outputLines(commits) {
+				plan.Changes = append(plan.Changes, "  将离开分支的提交: "+line)
+			}
+		}
+		if commits, err := e.gitOutput("log", "--oneline", tip+".."+target.Head); err == nil {
+			for _, line := range outputLines(commits) {
+				plan.Changes = append(plan.Changes, "  将恢复的提交: "+line)
+			}
+		}
+	}
+
+	// 3. 其他分支
+	names := make(map[string]bool)
+	for name := range target.Branches {
+		names[name] = true
+	}
+	for name := range current.Branches {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		if name != target.Branch {
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		before, existed := target.Branches[name]
+		now, exists := current.Branches[name]
+		ref := "refs/heads/" + name
+		switch {
+		case existed && exists && before != now:
+			plan.add([]string{"update-ref", ref, before, now}, fmt.Sprintf("分支 %s: %s → %s", name, shortSHA(now), shortSHA(before)))
+		case existed && !exists:
+			plan.add([]string{"update-ref", ref, before}, fmt.Sprintf("恢复已删除的分支 %s (%s)", name, shortSHA(before)))
+		case !existed && exists:
+			plan.add([]string{"update-ref", "-d", ref, now}, fmt.Sprintf("删除操作中创建的分支 %s (%s)", name, shortSHA(now)))
+		}
+	}
+
+	// 4. 储藏：操作中新增的储藏重新应用到工作区，被取出的储藏放回储藏列表
+	switch {
+	case current.StashCount == target.StashCount+1:
+		plan.add([]string{"stash", "pop"}, "将操作中储藏的修改恢复到工作区")
+	case current.StashCount == target.StashCount-1 && target.Stash != "":
+		plan.add([]string{"stash", "store", "-m", "xgit cx 恢复的储藏", target.Stash}, fmt.Sprintf("恢复操作中取出的储藏 %s", shortSHA(target.Stash)))
+	}
+
+	return plan
+}
+
+// 撤销最近的xgit操作：xgit cx [操作数]，xgit cx --list 查看日志。
+// 日志中没有可撤销的操作时根据 reflog 回退
+func (e *Engine) undoOperations(cmdName string, spec CompositeCommand, args []string) error {
+	count := 0
+	for _, arg := range args {
+		if arg == "-l" || arg == "--list" {
+			return e.listJournal()
+		}
+		if count != 0 {
+			return &extraArgsError{Args: []string{arg}}
+		}
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("操作数必须是正整数: %s", arg)
+		}
+		count = n
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	current, err := e.captureRepoState()
+	if err != nil {
+		return fmt.Errorf("当前目录不是git仓库")
+	}
+	entries, err := e.readJournal()
+	if err != nil {
+		return fmt.Errorf("无法读取操作日志: %v", err)
+	}
+
+	var pending []int
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].Undone {
+			pending = append(pending, i)
+		}
+	}
+
+	var plan restorePlan
+	if len(pending) == 0 {
+		fmt.Fprintln(e.Stdout, "操作日志中没有可撤销的操作，根据 reflog 回退:")
+		plan, err = e.reflogPlan(count, current)
+		if err != nil {
+			return err
+		}
+	} else {
+		if count > len(pending) {
+			return fmt.Errorf("操作日志中只有 %d 个可撤销的操作", len(pending))
+		}
+		pending = pending[:count]
+		latest, oldest := entries[pending[0]], entries[pending[len(pending)-1]]
+
+		fmt.Fprintln(e.Stdout, "将撤销以下操作:")
+		for _, i := range pending {
+			fmt.Fprintf(e.Stdout, "  %s  xgit %s\n", entries[i].Time.Format("2006-01-02 15:04:05"), strings.Join(entries[i].Command, " "))
+		}
+		if !latest.After.sameRefs(current) {
+			fmt.Fprintln(e.Stdout, "⚠️  仓库在最后一次xgit操作之后又有变化，这些变化也会被撤销")
+		}
+		plan = e.planRestore(current, oldest.Before)
+	}
+
+	if len(plan.Steps) == 0 {
+		fmt.Fprintln(e.Stdout, "仓库已处于操作前的状态，无需撤销")
+		return e.markUndone(entries, pending)
+	}
+
+	fmt.Fprintln(e.Stdout, "撤销后的变化:")
+	for _, change := range plan.Changes {
+		fmt.Fprintf(e.Stdout, "  %s\n", change)
+	}
+	if e.confirmationEnabled() && !e.askConfirmation("确定要撤销吗？[y/N] ") {
+		return ErrAborted
+	}
+
+	total := len(plan.Steps)
+	for i, step := range plan.Steps {
+		if !e.DryRun {
+			fmt.Fprintf(e.Stdout, "→ [%d/%d] %s\n", i+1, total, formatGitCommand(step))
+		}
+		if err := e.executeGitCommandWithError(step); err != nil {
+			return newStepError(cmdName, i+1, total, step, err)
+		}
+	}
+	if e.DryRun {
+		return nil
+	}
+	return e.markUndone(entries, pending)
+}
+
+// 将日志中的操作标记为已撤销
+func (e *Engine) markUndone(entries []journalEntry, indexes []int) error {
+	if len(indexes) == 0 || e.DryRun {
+		return nil
+	}
+	for _, i := range indexes {
+		entries[i].Undone = true
+	}
+	if err := e.writeJournal(entries); err != nil {
+		return fmt.Errorf("无法更新操作日志: %v", err)
+	}
+	return nil
+}
+
+// 根据 reflog 计算回退 count 步的恢复步骤。回退范围内有切换分支的记录时，
+// 回到最早一次切换之前所在的分支
+func (e *Engine) reflogPlan(count int, current repoState) (restorePlan, error) {
+	out, err := e.gitOutput("reflog", "-n", strconv.Itoa(count+1), "--format=%H %gs")
+	if err != nil {
+		return restorePlan{}, fmt.Errorf("无法读取 reflog: %v", err)
+	}
+	lines := outputLines(out)
+	if len(lines) <= count {
+		return restorePlan{}, fmt.Errorf("reflog 中没有足够的记录可以撤销 %d 步", count)
+	}
+
+	target := current
+	target.Head, _, _ = strings.Cut(lines[count], " ")
+	for i := 0; i < count; i++ {
+		_, message, _ := strings.Cut(lines[i], " ")
+		fmt.Fprintf(e.Stdout, "  HEAD@{%d}: %s\n", i, message)
+		if rest, ok := strings.CutPrefix(message, "checkout: moving from "); ok {
+			from, _, _ := strings.Cut(rest, " to ")
+			target.Branch = from
+			// 从分离头指针切换时记录的是提交哈希
+			if _, isBranch := current.Branches[from]; !isBranch && isCommitHash(from) {
+				target.Branch = ""
+			}
+		}
+	}
+	return e.planRestore(current, target), nil
+}
+
+// 是否为完整的提交哈希
+func isCommitHash(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// 列出操作日志，最近的操作在前
+func (e *Engine) listJournal() error {
+	entries, err := e.readJournal()
+	if err != nil {
+		return fmt.Errorf("无法读取操作日志: %v", err)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(e.Stdout, "还没有记录任何操作")
+		return nil
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		mark := ""
+		if entry.Undone {
+			mark = "（已撤销）"
+		}
+		fmt.Fprintf(e.Stdout, "%s  %s → %s  xgit %s%s\n", entry.Time.Format("2006-01-02 15:04:05"),
+			shortSHA(entry.Before.Head), shortSHA(entry.After.Head), strings.Join(entry.Command, " "), mark)
+	}
+	return nil
+}
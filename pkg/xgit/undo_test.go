@@ -0,0 +1,242 @@
+package xgit
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// 像命令行一样执行并记录一次xgit操作
+func runJournaled(t *testing.T, e *Engine, args ...string) {
+	t.Helper()
+	var err error
+	output := captureOutput(e, func() {
+		err = e.dispatch(args)
+	})
+	if err != nil {
+		t.Fatalf("xgit %s 失败: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+// 执行撤销，自动确认
+func runUndo(t *testing.T, e *Engine, args ...string) (string, error) {
+	t.Helper()
+	e.AssumeYes = true
+	var err error
+	output := captureOutput(e, func() {
+		err = e.Execute("cx", args)
+	})
+	return output, err
+}
+
+func TestUndo_ResetHard(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	commitFile(t, dir, "second.txt", "第二个提交\n")
+	before := runGitIn(t, dir, "rev-parse", "HEAD")
+
+	e.AssumeYes = true
+	runJournaled(t, e, "ht", "--hard", "HEAD~1")
+	e.AssumeYes = false
+
+	output, err := runUndo(t, e)
+	if err != nil {
+		t.Fatalf("撤销失败: %v\n%s", err, output)
+	}
+	for _, element := range []string{"将撤销以下操作:", "xgit ht --hard HEAD~1", "将恢复的提交:", "更新 second.txt", "reset --keep " + before} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+	if head := runGitIn(t, dir, "rev-parse", "HEAD"); head != before {
+		t.Errorf("撤销后 HEAD 应该回到 %s，得到 %s", before, head)
+	}
+
+	entries, _ := e.readJournal()
+	if len(entries) != 1 || !entries[0].Undone {
+		t.Errorf("撤销后日志中的操作应该标记为已撤销，得到 %+v", entries)
+	}
+}
+
+func TestUndo_MultipleOperations(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	before := runGitIn(t, dir, "rev-parse", "HEAD")
+
+	runJournaled(t, e, "cjfz", "feature")
+	runJournaled(t, e, "qhfz", "main")
+	commitFile(t, dir, "later.txt", "之后的提交\n")
+
+	output, err := runUndo(t, e, "2")
+	if err != nil {
+		t.Fatalf("撤销失败: %v\n%s", err, output)
+	}
+	for _, element := range []string{"⚠️  仓库在最后一次xgit操作之后又有变化", "将离开分支的提交:", "删除操作中创建的分支 feature"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+	if head := runGitIn(t, dir, "rev-parse", "HEAD"); head != before {
+		t.Errorf("撤销后 HEAD 应该回到 %s，得到 %s", before, head)
+	}
+	if e.refExists("refs/heads/feature") {
+		t.Error("撤销后操作中创建的分支应该被删除")
+	}
+}
+
+func TestUndo_Stash(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	writeWorkFile(t, dir, "README.md", "未提交的修改\n")
+
+	runJournaled(t, e, "git", "stash")
+	if content, _ := os.ReadFile("README.md"); string(content) != "初始内容\n" {
+		t.Fatal("git stash 应该清理工作区")
+	}
+
+	output, err := runUndo(t, e)
+	if err != nil {
+		t.Fatalf("撤销失败: %v\n%s", err, output)
+	}
+	if content, _ := os.ReadFile("README.md"); string(content) != "未提交的修改\n" {
+		t.Errorf("撤销储藏后应该恢复工作区的修改，实际输出:\n%s", output)
+	}
+}
+
+func TestUndo_ReflogFallback(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	commitFile(t, dir, "second.txt", "第二个提交\n")
+	before := runGitIn(t, dir, "rev-parse", "HEAD")
+	runGitIn(t, dir, "reset", "-q", "--hard", "HEAD~1")
+
+	output, err := runUndo(t, e)
+	if err != nil {
+		t.Fatalf("撤销失败: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "根据 reflog 回退") || !strings.Contains(output, "HEAD@{0}: reset: moving to HEAD~1") {
+		t.Errorf("没有日志时应该根据 reflog 回退，实际输出:\n%s", output)
+	}
+	if head := runGitIn(t, dir, "rev-parse", "HEAD"); head != before {
+		t.Errorf("撤销后 HEAD 应该回到 %s，得到 %s", before, head)
+	}
+}
+
+func TestUndo_Aborted(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	runJournaled(t, e, "cjfz", "feature")
+	e.Stdin = strings.NewReader("n\n")
+
+	var err error
+	output := captureOutput(e, func() { err = e.Execute("cx", nil) })
+	if !errors.Is(err, ErrAborted) {
+		t.Errorf("取消时应该返回 ErrAborted，得到 %v", err)
+	}
+	if !strings.Contains(output, "删除操作中创建的分支 feature") || !strings.Contains(output, "确定要撤销吗？[y/N]") {
+		t.Errorf("应该在确认前预览变化，实际输出:\n%s", output)
+	}
+	if !e.refExists("refs/heads/feature") {
+		t.Error("取消后不应该修改仓库")
+	}
+	runGitIn(t, dir, "rev-parse", "feature")
+}
+
+func TestUndo_List(t *testing.T) {
+	e := newTestEngine(t)
+	setupGuardRepo(t)
+	runJournaled(t, e, "cjfz", "feature")
+	runJournaled(t, e, "zt")
+	runUndo(t, e)
+
+	output, err := runUndo(t, e, "--list")
+	if err != nil {
+		t.Fatalf("查看日志失败: %v", err)
+	}
+	if !strings.Contains(output, "xgit cjfz feature（已撤销）") {
+		t.Errorf("日志中应该列出已撤销的操作，实际输出:\n%s", output)
+	}
+	if strings.Contains(output, "xgit zt") {
+		t.Errorf("没有改变仓库状态的操作不应该被记录，实际输出:\n%s", output)
+	}
+}
+
+func TestUndo_TooMany(t *testing.T) {
+	e := newTestEngine(t)
+	setupGuardRepo(t)
+	runJournaled(t, e, "cjfz", "feature")
+
+	if _, err := runUndo(t, e, "3"); err == nil || !strings.Contains(err.Error(), "只有 1 个可撤销的操作") {
+		t.Errorf("超过日志中的操作数时应该报错，得到 %v", err)
+	}
+	if _, err := runUndo(t, e, "abc"); err == nil {
+		t.Error("操作数不是数字时应该报错")
+	}
+}
+
+func TestUndo_CreateBranch(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	runJournaled(t, e, "cjfz", "feature")
+
+	output, err := runUndo(t, e)
+	if err != nil {
+		t.Fatalf("撤销失败: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "切换回分支 main") {
+		t.Errorf("应该预览切换回原分支，实际输出:\n%s", output)
+	}
+	if branch := runGitIn(t, dir, "symbolic-ref", "--short", "HEAD"); branch != "main" {
+		t.Errorf("撤销后应该回到 main，当前分支 %s", branch)
+	}
+	if e.refExists("refs/heads/feature") {
+		t.Error("撤销后操作中创建的分支应该被删除")
+	}
+}
+
+// 记录调用的git命令并交给真实的git执行
+type countingRunner struct {
+	calls [][]string
+}
+
+func (r *countingRunner) Run(cmd GitCommand) (GitResult, error) {
+	r.calls = append(r.calls, cmd.Args)
+	return ExecRunner{}.Run(cmd)
+}
+
+func TestCaptureRepoState(t *testing.T) {
+	e := newTestEngine(t)
+	dir := setupGuardRepo(t)
+	runner := &countingRunner{}
+	e.Runner = runner
+	head := runGitIn(t, dir, "rev-parse", "HEAD")
+
+	// 每个xgit命令执行前后都会读取状态，通常只需要一次git调用，不扫描工作区
+	state, err := e.captureRepoState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Branch != "main" || state.Head != head || state.Branches["main"] != head || state.Stash != "" {
+		t.Errorf("读取的状态不正确: %+v", state)
+	}
+	if len(runner.calls) != 1 {
+		t.Errorf("在分支上只应该调用一次git，得到 %v", runner.calls)
+	}
+
+	writeWorkFile(t, dir, "README.md", "未提交的修改\n")
+	runGitIn(t, dir, "stash", "-q")
+	runGitIn(t, dir, "checkout", "-q", "--detach")
+	if state, err = e.captureRepoState(); err != nil {
+		t.Fatal(err)
+	}
+	stash := runGitIn(t, dir, "rev-parse", "refs/stash")
+	if state.Branch != "" || state.Head != head || state.Stash != stash || state.StashCount != 1 {
+		t.Errorf("分离头指针和储藏的状态不正确: %+v", state)
+	}
+
+	e.Dir = t.TempDir()
+	if _, err := e.captureRepoState(); err == nil {
+		t.Error("不在仓库中时应该返回错误")
+	}
+}
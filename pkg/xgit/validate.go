@@ -0,0 +1,553 @@
+package xgit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidConfig 表示配置检查发现了错误
+var ErrInvalidConfig = errors.New("配置检查未通过")
+
+// 看起来像占位符但无法识别的写法，如 {branh}；git 自身的 @{upstream} 等不算
+var unknownPlaceholderPattern = regexp.MustCompile(`(^|[^@])\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// 待检查的配置文件
+type configSource struct {
+	Name  string // 配置层名称
+	Path  string // 文件路径，内置默认配置为空
+	Data  []byte
+	Quiet bool // 只作为检查其他文件的基础，不报告其中的问题
+}
+
+// 问题中显示的文件名
+func (s configSource) display() string {
+	if s.Path == "" {
+		return s.Name
+	}
+	return s.Path
+}
+
+// 配置检查发现的问题
+type configIssue struct {
+	Source  int // 所在文件在检查列表中的序号
+	File    string
+	Offset  int // 在文件中的字节偏移，-1 表示没有具体位置
+	Line    int
+	Column  int
+	Warning bool
+	Message string
+}
+
+func (i configIssue) String() string {
+	level := "错误"
+	if i.Warning {
+		level = "警告"
+	}
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", i.File, level, i.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", i.File, i.Line, i.Column, level, i.Message)
+}
+
+// JSON文档中的一个位置
+type jsonLocation struct {
+	Path   string
+	Offset int
+}
+
+// JSON文档中各个值的位置，以 /commands/kl/args 形式的路径索引。
+// offsets 中对象成员记录键的位置，数组元素记录元素本身的位置
+type jsonIndex struct {
+	offsets    map[string]int
+	starts     map[string]int      // 值的起始位置
+	ends       map[string]int      // 值的结束位置（不含）
+	members    map[string][]string // 对象成员或数组元素的路径，按出现顺序
+	duplicates []jsonLocation      // 同一对象中重复出现的键（后出现的位置）
+
+	data []byte
+	dec  *json.Decoder
+}
+
+// 逐个读取JSON记号，记录每个值的位置
+func indexJSON(data []byte) (*jsonIndex, error) {
+	x := &jsonIndex{
+		offsets: make(map[string]int),
+		starts:  make(map[string]int),
+		ends:    make(map[string]int),
+		members: make(map[string][]string),
+		data:    data,
+		dec:     json.NewDecoder(bytes.NewReader(data)),
+	}
+	return x, x.walk("", x.next())
+}
+
+// 下一个记号的起始位置，跳过空白以及尚未读取的逗号和冒号
+func (x *jsonIndex) next() int {
+	offset := int(x.dec.InputOffset())
+	for offset < len(x.data) && strings.IndexByte(" \t\r\n,:", x.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func (x *jsonIndex) walk(path string, offset int) error {
+	x.offsets[path] = offset
+	x.starts[path] = x.next()
+
+	token, err := x.dec.Token()
+	if err != nil {
+		return err
+	}
+	switch token {
+	case json.Delim('{'):
+		seen := make(map[string]bool)
+		for x.dec.More() {
+			keyOffset := x.next()
+			token, err := x.dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+			child := path + "/" + key
+			if seen[key] {
+				x.duplicates = append(x.duplicates, jsonLocation{Path: child, Offset: keyOffset})
+			} else {
+				x.members[path] = append(x.members[path], child)
+			}
+			seen[key] = true
+			if err := x.walk(child, keyOffset); err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; x.dec.More(); i++ {
+			child := path + "/" + strconv.Itoa(i)
+			x.members[path] = append(x.members[path], child)
+			if err := x.walk(child, x.next()); err != nil {
+				return err
+			}
+		}
+	default:
+		x.ends[path] = int(x.dec.InputOffset())
+		return nil
+	}
+	// 读取结束的 } 或 ]
+	if _, err := x.dec.Token(); err != nil {
+		return err
+	}
+	x.ends[path] = int(x.dec.InputOffset())
+	return nil
+}
+
+// 查找路径的位置，路径不存在时使用最近的上级
+func (x *jsonIndex) locate(path string) int {
+	for {
+		if offset, exists := x.offsets[path]; exists {
+			return offset
+		}
+		if path == "" {
+			return -1
+		}
+		path = path[:strings.LastIndex(path, "/")]
+	}
+}
+
+// 将 /commands/kl 形式的路径显示为 commands.kl
+func jsonPathName(path string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(path, "/"), "/", ".")
+}
+
+// 字节偏移对应的行号和列号（按字符计），均从 1 开始
+func lineColumn(data []byte, offset int) (int, int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return line, utf8.RuneCount(before[lineStart:]) + 1
+}
+
+// 配置检查的过程状态
+type configChecker struct {
+	sources []configSource
+	indexes []*jsonIndex
+	issues  []configIssue
+}
+
+// 在文件的字节偏移处记录一个问题
+func (c *configChecker) reportAt(source, offset int, warning bool, format string, args ...any) {
+	src := c.sources[source]
+	if src.Quiet {
+		return
+	}
+	issue := configIssue{Source: source, File: src.display(), Offset: offset, Warning: warning, Message: fmt.Sprintf(format, args...)}
+	if offset >= 0 {
+		issue.Line, issue.Column = lineColumn(src.Data, offset)
+	}
+	c.issues = append(c.issues, issue)
+}
+
+// 在JSON路径所在的位置记录一个问题
+func (c *configChecker) report(source int, path string, warning bool, format string, args ...any) {
+	offset := -1
+	if index := c.indexes[source]; index != nil {
+		offset = index.locate(path)
+	}
+	c.reportAt(source, offset, warning, format, args...)
+}
+
+// 解析一个配置文件，报告语法错误、类型错误和文件内的重复定义。无法解析时返回 nil
+func (c *configChecker) parse(source int) *CommandConfig {
+	data := c.sources[source].Data
+	cfg := &CommandConfig{}
+	decodeErr := json.Unmarshal(data, cfg)
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(decodeErr, &syntaxErr) {
+		// Offset 包含出错的字符
+		c.reportAt(source, max(int(syntaxErr.Offset)-1, 0), false, "JSON 语法错误: %v", syntaxErr)
+		return nil
+	}
+	index, err := indexJSON(data)
+	if err != nil {
+		c.reportAt(source, len(data), false, "JSON 语法错误: %v", err)
+		return nil
+	}
+	c.indexes[source] = index
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(decodeErr, &typeErr) {
+		if typeErr.Field == "" {
+			c.reportAt(source, index.locate(""), false, "配置文件的顶层应该是 JSON 对象，而不是 JSON %s", typeErr.Value)
+			return nil
+		}
+		path := "/" + strings.ReplaceAll(typeErr.Field, ".", "/")
+		c.reportAt(source, index.locate(path), false, "%s 的类型应该是 %s，而不是 JSON %s", typeErr.Field, typeErr.Type, typeErr.Value)
+		return nil
+	}
+	if decodeErr != nil {
+		c.reportAt(source, -1, false, "无法解析: %v", decodeErr)
+		return nil
+	}
+
+	for _, dup := range index.duplicates {
+		c.reportAt(source, dup.Offset, false, "重复的键 %s，前面的定义会被忽略", jsonPathName(dup.Path))
+	}
+	for _, name := range sortedKeys(cfg.Commands) {
+		if _, exists := cfg.CompositeCommands[name]; exists {
+			c.report(source, "/composite_commands/"+name, false, "命令 %s 同时定义在 commands 和 composite_commands 中", name)
+		}
+	}
+	return cfg
+}
+
+// 检查合并后的命令定义，问题报告在命令最终生效的那个文件中
+func (c *configChecker) checkCommands(cfg *CommandConfig, origin map[string]int) {
+	e := &Engine{config: cfg}
+	e.generateMappings()
+
+	declared := make(map[string]bool)
+	for _, category := range cfg.Categories {
+		declared[category.Name] = true
+	}
+	claims := nameClaims(cfg)
+
+	for _, name := range sortedKeys(origin) {
+		source := origin[name]
+		var path, category, description string
+		if cmd, exists := cfg.Commands[name]; exists {
+			path, category, description = "/commands/"+name, cmd.Category, cmd.Description
+			if len(cmd.Args) == 0 {
+				c.report(source, path+"/args", false, "命令 %s 的 args 为空", name)
+			}
+		} else {
+			cmd := cfg.CompositeCommands[name]
+			path, category, description = "/composite_commands/"+name, cmd.Category, cmd.Description
+			c.checkSteps(source, path, name, cmd)
+		}
+
+		switch {
+		case isBuiltinCommand(name):
+			c.report(source, path, false, "命令 %s 与 xgit 自身的命令重名，永远不会被执行", name)
+		case e.isGitCommand(name):
+			c.report(source, path, true, "命令 %s 遮蔽了 git_commands 中的同名命令，xgit %s 不会再执行 git %s", name, name, name)
+		}
+
+		if category == "" {
+			c.report(source, path, true, "命令 %s 没有设置分类", name)
+		} else if !declared[category] {
+			c.report(source, path+"/category", true, "命令 %s 的分类 %s 没有在 categories 中声明", name, category)
+		}
+
+		for _, problem := range e.checkCommandExamples(name) {
+			c.report(source, path+"/examples", true, "%s", problem)
+		}
+
+		if problem := checkDescriptionPinyin(name, description); problem != "" {
+			c.report(source, path+"/description", true, "%s", problem)
+		}
+
+		c.checkNames(e, claims, source, path, name)
+	}
+}
+
+// 检查命令的其他名称是否与其他命令冲突
+func (c *configChecker) checkNames(e *Engine, claims map[string][]string, source int, path, name string) {
+	seen := make(map[string]bool)
+	for i, alternate := range e.config.commandNames(name) {
+		namePath := fmt.Sprintf("%s/names/%d", path, i)
+		key := normalizeName(alternate)
+		if key == name || seen[key] {
+			c.report(source, namePath, true, "命令 %s 的其他名称 %q 重复", name, alternate)
+			continue
+		}
+		seen[key] = true
+		if conflict := e.nameConflict(key, claims[key]); conflict != "" {
+			c.report(source, namePath, false, "命令 %s 的其他名称 %q %s，不会生效", name, alternate, conflict)
+		}
+	}
+}
+
+// 检查复合命令的步骤、占位符和处理器
+func (c *configChecker) checkSteps(source int, path, name string, cmd CompositeCommand) {
+	if cmd.Handler != "" {
+		if _, exists := compositeHandlers[cmd.Handler]; !exists {
+			c.report(source, path+"/handler", false, "复合命令 %s 使用了未知的处理器 %s", name, cmd.Handler)
+		}
+	}
+	if len(cmd.Steps) == 0 {
+		c.report(source, path+"/steps", false, "复合命令 %s 没有任何步骤", name)
+		return
+	}
+
+	used := make(map[int]bool)
+	for i, step := range cmd.Steps {
+		stepPath := fmt.Sprintf("%s/steps/%d", path, i)
+		if len(step) == 0 {
+			c.report(source, stepPath, false, "复合命令 %s 的第 %d 步为空", name, i+1)
+			continue
+		}
+		for j, arg := range step {
+			argPath := fmt.Sprintf("%s/%d", stepPath, j)
+			if arg != restPlaceholder && strings.Contains(arg, restPlaceholder) {
+				c.report(source, argPath, false, "复合命令 %s 的第 %d 步中 %s 必须单独作为一个参数", name, i+1, restPlaceholder)
+			}
+			for _, m := range findPlaceholders(arg) {
+				if m[2] < 0 {
+					continue
+				}
+				n, _ := strconv.Atoi(arg[m[2]:m[3]])
+				if n == 0 {
+					c.report(source, argPath, false, "复合命令 %s 的第 %d 步使用了 %s，参数从 {1} 开始编号", name, i+1, arg[m[0]:m[1]])
+					continue
+				}
+				used[n] = true
+			}
+			for _, m := range unknownPlaceholderPattern.FindAllStringSubmatch(arg, -1) {
+				if m[2] == "args" || m[2] == "branch" {
+					continue
+				}
+				c.report(source, argPath, false, "复合命令 %s 的第 %d 步使用了未知的占位符 {%s}", name, i+1, m[2])
+			}
+		}
+	}
+
+	// 使用处理器的命令自行解释参数
+	if cmd.Handler != "" {
+		return
+	}
+	maxIndex, usesRest := placeholderUsage(cmd.Steps)
+	for n := 1; n < maxIndex; n++ {
+		if !used[n] {
+			c.report(source, path+"/steps", true, "复合命令 %s 没有使用第 %d 个参数，但执行时仍然必须提供它", name, n)
+		}
+	}
+	if len(cmd.Params) == 0 {
+		return
+	}
+	if maxIndex > len(cmd.Params) {
+		c.report(source, path+"/params", true, "复合命令 %s 的步骤使用了第 %d 个参数，但 params 只说明了 %d 个", name, maxIndex, len(cmd.Params))
+	} else if len(cmd.Params) > maxIndex && !usesRest {
+		c.report(source, path+"/params", true, "复合命令 %s 的参数 %s 没有在步骤中使用，提供它时会报告多余的参数", name, cmd.Params[maxIndex])
+	}
+}
+
+// 依次检查每个配置文件，再按合并后的结果检查命令定义
+func checkConfigSources(sources []configSource) []configIssue {
+	c := &configChecker{sources: sources, indexes: make([]*jsonIndex, len(sources))}
+
+	merged := &CommandConfig{}
+	origin := make(map[string]int) // 命令最终生效的定义所在的文件
+	var builtin *CommandConfig
+	for i := range sources {
+		cfg := c.parse(i)
+		if cfg == nil {
+			continue
+		}
+		if sources[i].Path == "" {
+			builtin = cfg
+		} else if builtin != nil {
+			c.checkShadowedHandlers(i, builtin, cfg)
+		}
+		mergeConfig(merged, cfg)
+		for name := range cfg.Commands {
+			origin[name] = i
+		}
+		for name := range cfg.CompositeCommands {
+			origin[name] = i
+		}
+	}
+	c.checkCommands(merged, origin)
+
+	sort.SliceStable(c.issues, func(i, j int) bool {
+		if c.issues[i].Source != c.issues[j].Source {
+			return c.issues[i].Source < c.issues[j].Source
+		}
+		return c.issues[i].Offset < c.issues[j].Offset
+	})
+	return c.issues
+}
+
+// 检查配置文件是否覆盖了使用处理器的内置复合命令：覆盖后只剩下 steps，
+// 内置命令的选项和参数检查都不再生效，通常是旧版本留下的配置副本
+func (c *configChecker) checkShadowedHandlers(source int, builtin, cfg *CommandConfig) {
+	for _, name := range sortedKeys(builtin.CompositeCommands) {
+		handler := builtin.CompositeCommands[name].Handler
+		if handler == "" {
+			continue
+		}
+		if _, exists := cfg.Commands[name]; exists {
+			c.report(source, "/commands/"+name, true, "命令 %s 覆盖了使用处理器 %s 的内置命令，内置命令的选项和参数检查不再生效", name, handler)
+		} else if cmd, exists := cfg.CompositeCommands[name]; exists && cmd.Handler != handler {
+			c.report(source, "/composite_commands/"+name, true, "命令 %s 覆盖了使用处理器 %s 的内置命令，内置命令的选项和参数检查不再生效", name, handler)
+		}
+	}
+}
+
+// 按名称排序的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// 配置相关的命令
+func (e *Engine) configCommand(args []string) error {
+	if len(args) == 0 {
+		e.showConfigUsage()
+		return nil
+	}
+	switch args[0] {
+	case "jc":
+		return e.checkConfig(args[1:])
+	case "schema":
+		return e.showSchema()
+	default:
+		fmt.Fprintf(e.Stdout, "未知的配置命令: %s\n", args[0])
+		e.showConfigUsage()
+		return ErrUnknownCommand
+	}
+}
+
+func (e *Engine) showConfigUsage() {
+	fmt.Fprintln(e.Stdout, "用法:")
+	fmt.Fprintln(e.Stdout, "  xgit pz jc [--strict] [配置文件...]  # 检查配置文件 (jian cha)")
+	fmt.Fprintln(e.Stdout, "  xgit pz schema                       # 输出配置文件的 JSON Schema")
+}
+
+// 检查配置文件：未指定文件时检查内置配置和所有存在的配置层，
+// 指定文件时以内置配置为基础只检查这些文件。发现错误时返回 ErrInvalidConfig，
+// --strict 时警告也视为失败
+func (e *Engine) checkConfig(args []string) error {
+	strict := false
+	var files []string
+	for _, arg := range args {
+		if arg == "--strict" {
+			strict = true
+			continue
+		}
+		files = append(files, arg)
+	}
+
+	sources := []configSource{{Name: "内置默认配置", Data: defaultConfigData, Quiet: len(files) > 0}}
+	failed := false
+	legacyPath := "" // 旧的安装方式留下的配置
+	addSource := func(name, path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(e.Stdout, "%s: 错误: 无法读取%s: %v\n", path, name, err)
+			failed = true
+			return
+		}
+		sources = append(sources, configSource{Name: name, Path: path, Data: data})
+	}
+
+	if len(files) == 0 {
+		for _, layer := range configLayers(e.Dir) {
+			if _, err := os.Stat(layer.Path); errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			addSource(layer.Name, layer.Path)
+		}
+		if path := legacyConfigPath(); path != "" {
+			if _, err := os.Stat(path); err == nil {
+				legacyPath = path
+			}
+		}
+	} else {
+		for _, file := range files {
+			if e.Dir != "" && !filepath.IsAbs(file) {
+				file = filepath.Join(e.Dir, file)
+			}
+			addSource("配置文件", file)
+		}
+	}
+
+	for _, source := range sources {
+		switch {
+		case source.Quiet:
+		case source.Path == "":
+			fmt.Fprintf(e.Stdout, "检查%s\n", source.Name)
+		default:
+			fmt.Fprintf(e.Stdout, "检查%s: %s\n", source.Name, source.Path)
+		}
+	}
+
+	errorCount, warningCount := 0, 0
+	if legacyPath != "" {
+		fmt.Fprintf(e.Stdout, "%s: 警告: 执行文件旁边的 commands.json 已不再加载，其中的自定义命令请移到用户配置\n", legacyPath)
+		warningCount++
+	}
+	for _, issue := range checkConfigSources(sources) {
+		fmt.Fprintln(e.Stdout, issue)
+		if issue.Warning {
+			warningCount++
+		} else {
+			errorCount++
+		}
+	}
+
+	if errorCount == 0 && warningCount == 0 && !failed {
+		fmt.Fprintln(e.Stdout, "配置检查通过")
+		return nil
+	}
+	fmt.Fprintf(e.Stdout, "发现 %d 个错误，%d 个警告\n", errorCount, warningCount)
+	if failed || errorCount > 0 || (strict && warningCount > 0) {
+		return ErrInvalidConfig
+	}
+	return nil
+}
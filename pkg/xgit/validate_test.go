@@ -0,0 +1,245 @@
+package xgit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 以内置配置为基础检查一份配置内容，返回问题的文本
+func checkConfigText(t *testing.T, content string) []string {
+	t.Helper()
+	sources := []configSource{
+		{Name: "内置默认配置", Data: defaultConfigData, Quiet: true},
+		{Name: "配置文件", Path: "test.json", Data: []byte(content)},
+	}
+	var issues []string
+	for _, issue := range checkConfigSources(sources) {
+		issues = append(issues, issue.String())
+	}
+	return issues
+}
+
+// 检查问题列表中包含 want
+func assertIssue(t *testing.T, issues []string, want string) {
+	t.Helper()
+	for _, issue := range issues {
+		if strings.Contains(issue, want) {
+			return
+		}
+	}
+	t.Errorf("应该报告 %q，实际:\n%s", want, strings.Join(issues, "\n"))
+}
+
+func TestCheckConfigSources_DefaultConfig(t *testing.T) {
+	issues := checkConfigSources([]configSource{{Name: "内置默认配置", Data: defaultConfigData}})
+	for _, issue := range issues {
+		t.Errorf("默认配置不应该有问题: %s", issue)
+	}
+}
+
+func TestCheckConfigSources_Duplicates(t *testing.T) {
+	issues := checkConfigText(t, `{
+  "commands": {
+    "ck": {"args": ["status"], "category": "状态操作"},
+    "ck": {"args": ["log"], "category": "日志操作"},
+    "tb": {"args": ["fetch"], "category": "远程操作"}
+  },
+  "composite_commands": {
+    "tb": {"steps": [["fetch"]], "category": "远程操作"}
+  }
+}`)
+
+	assertIssue(t, issues, "test.json:4:5: 错误: 重复的键 commands.ck")
+	assertIssue(t, issues, "test.json:8:5: 错误: 命令 tb 同时定义在 commands 和 composite_commands 中")
+}
+
+func TestCheckConfigSources_Commands(t *testing.T) {
+	issues := checkConfigText(t, `{
+  "commands": {
+    "kong": {"args": [], "category": "状态操作"},
+    "status": {"args": ["status", "-s"], "category": "状态操作"},
+    "bz": {"args": ["branch"], "category": "分支操作"},
+    "wfl": {"args": ["log"], "category": "常用"},
+    "lz": {"args": ["log"], "category": "日志操作", "examples": [{"command": "xgit zl"}]}
+  }
+}`)
+
+	assertIssue(t, issues, "test.json:3:14: 错误: 命令 kong 的 args 为空")
+	assertIssue(t, issues, "test.json:4:5: 警告: 命令 status 遮蔽了 git_commands 中的同名命令")
+	assertIssue(t, issues, "test.json:5:5: 错误: 命令 bz 与 xgit 自身的命令重名")
+	assertIssue(t, issues, "test.json:6:30: 警告: 命令 wfl 的分类 常用 没有在 categories 中声明")
+	assertIssue(t, issues, "test.json:7:49: 警告: 命令 lz 的示例 \"xgit zl\" 引用了未知命令 zl")
+}
+
+func TestCheckConfigSources_Steps(t *testing.T) {
+	issues := checkConfigText(t, `{
+  "composite_commands": {
+    "xx": {
+      "steps": [["commit", "-m", "{0}"], ["push", "pre{args}"], ["log", "{branh}", "@{upstream}", "stash@{0}"], []],
+      "category": "复合命令"
+    },
+    "yy": {"steps": [["fetch", "{2}"]], "params": ["远程仓库名"], "category": "复合命令"},
+    "zz": {"steps": [["fetch"]], "params": ["远程仓库名"], "category": "复合命令"},
+    "ww": {"steps": [], "handler": "no-such-handler", "category": "复合命令"}
+  }
+}`)
+
+	assertIssue(t, issues, "test.json:4:34: 错误: 复合命令 xx 的第 1 步使用了 {0}")
+	assertIssue(t, issues, "test.json:4:51: 错误: 复合命令 xx 的第 2 步中 {args} 必须单独作为一个参数")
+	assertIssue(t, issues, "test.json:4:73: 错误: 复合命令 xx 的第 3 步使用了未知的占位符 {branh}")
+	assertIssue(t, issues, "错误: 复合命令 xx 的第 4 步为空")
+	assertIssue(t, issues, "警告: 复合命令 yy 没有使用第 1 个参数")
+	assertIssue(t, issues, "警告: 复合命令 yy 的步骤使用了第 2 个参数，但 params 只说明了 1 个")
+	assertIssue(t, issues, "警告: 复合命令 zz 的参数 远程仓库名 没有在步骤中使用")
+	assertIssue(t, issues, "错误: 复合命令 ww 使用了未知的处理器 no-such-handler")
+	assertIssue(t, issues, "错误: 复合命令 ww 没有任何步骤")
+
+	for _, issue := range issues {
+		if strings.Contains(issue, "upstream") || strings.Contains(issue, "第 3 步使用了 {0}") {
+			t.Errorf("git 的 @{upstream}、stash@{0} 不应该被当作占位符: %s", issue)
+		}
+	}
+}
+
+func TestCheckConfigSources_ShadowedHandlers(t *testing.T) {
+	issues := checkConfigText(t, `{
+  "commands": {
+    "ycsh": {"args": ["remote", "add", "origin"], "category": "远程操作"}
+  },
+  "composite_commands": {
+    "kstj": {"steps": [["add", "."], ["commit", "-m", "{1}"], ["push"]], "category": "常用命令"},
+    "tbfz": {"steps": [["pull", "--rebase"]], "handler": "sync-branch", "category": "分支操作"}
+  }
+}`)
+
+	assertIssue(t, issues, "test.json:3:5: 警告: 命令 ycsh 覆盖了使用处理器 setup-remote 的内置命令")
+	assertIssue(t, issues, "test.json:6:5: 警告: 命令 kstj 覆盖了使用处理器 quick-commit 的内置命令")
+	for _, issue := range issues {
+		if strings.Contains(issue, "tbfz") {
+			t.Errorf("保留了处理器的覆盖不应该报告: %s", issue)
+		}
+	}
+}
+
+func TestCheckConfigSources_DecodeErrors(t *testing.T) {
+	issues := checkConfigText(t, "{\n  \"commands\": {\n    \"a\": {\"args\": [\"x\"],}\n  }\n}")
+	assertIssue(t, issues, "test.json:3:25: 错误: JSON 语法错误")
+
+	issues = checkConfigText(t, "{\n  \"commands\": {\n    \"a\": {\"args\": \"status\"}\n  }\n}")
+	assertIssue(t, issues, "test.json:3:11: 错误: commands.a.args 的类型应该是 []string，而不是 JSON string")
+
+	issues = checkConfigText(t, "[]")
+	assertIssue(t, issues, "test.json:1:1: 错误: 配置文件的顶层应该是 JSON 对象")
+}
+
+func TestCheckConfigSources_Layers(t *testing.T) {
+	sources := []configSource{
+		{Name: "内置默认配置", Data: defaultConfigData},
+		{Name: "用户配置", Path: "user.json", Data: []byte(`{"categories": [{"name": "常用", "order": 1}]}`)},
+		{Name: "仓库配置", Path: "repo.json", Data: []byte(`{"commands": {"zt": {"args": [], "category": "常用"}}}`)},
+	}
+
+	issues := checkConfigSources(sources)
+	if len(issues) != 1 {
+		t.Fatalf("应该只有 1 个问题，得到 %v", issues)
+	}
+	// 分类在用户配置中声明，覆盖的命令在仓库配置中报告
+	if want := "repo.json:1:22: 错误: 命令 zt 的 args 为空"; issues[0].String() != want {
+		t.Errorf("期望 %q，得到 %q", want, issues[0].String())
+	}
+}
+
+func TestLineColumn(t *testing.T) {
+	data := []byte("{\n  \"说明\": \"克隆\"\n}")
+	offset := strings.Index(string(data), "\"克隆\"")
+	if line, column := lineColumn(data, offset); line != 2 || column != 9 {
+		t.Errorf("列号应该按字符计算，得到 %d:%d", line, column)
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	e := newTestEngine(t)
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	warn := filepath.Join(dir, "warn.json")
+	bad := filepath.Join(dir, "bad.json")
+	writeConfigFile(t, good, `{"commands": {"ck": {"args": ["status"], "category": "状态操作"}}}`)
+	writeConfigFile(t, warn, `{"commands": {"ck": {"args": ["status"], "category": "常用"}}}`)
+	writeConfigFile(t, bad, `{"commands": {"ck": {"args": [], "category": "状态操作"}}}`)
+
+	var err error
+	output := captureOutput(e, func() { err = e.configCommand([]string{"jc", good}) })
+	if err != nil || !strings.Contains(output, "配置检查通过") {
+		t.Errorf("没有问题时应该通过，得到 %v\n%s", err, output)
+	}
+
+	output = captureOutput(e, func() { err = e.configCommand([]string{"jc", warn}) })
+	if err != nil || !strings.Contains(output, "发现 0 个错误，1 个警告") {
+		t.Errorf("只有警告时不应该失败，得到 %v\n%s", err, output)
+	}
+	if err := e.configCommand([]string{"jc", "--strict", warn}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("--strict 时警告也应该失败，得到 %v", err)
+	}
+
+	output = captureOutput(e, func() { err = e.configCommand([]string{"jc", bad}) })
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(output, "发现 1 个错误，0 个警告") {
+		t.Errorf("有错误时应该返回 ErrInvalidConfig，得到 %v\n%s", err, output)
+	}
+
+	if err := e.configCommand([]string{"jc", filepath.Join(dir, "missing.json")}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("文件无法读取时应该失败，得到 %v", err)
+	}
+}
+
+func TestCheckConfig_Layers(t *testing.T) {
+	e := newTestEngine(t)
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(filepath.Join(xdg, "xgit"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeConfigFile(t, filepath.Join(xdg, "xgit", "commands.json"), `{"commands": {"ck": {"args": [], "category": "状态操作"}}`)
+
+	var err error
+	output := captureOutput(e, func() { err = e.configCommand([]string{"jc"}) })
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("用户配置有语法错误时应该失败，得到 %v", err)
+	}
+	for _, element := range []string{"检查内置默认配置", "检查用户配置: " + filepath.Join(xdg, "xgit", "commands.json"), "错误: JSON 语法错误"} {
+		if !strings.Contains(output, element) {
+			t.Errorf("输出中缺少元素: %s\n实际输出:\n%s", element, output)
+		}
+	}
+}
+
+func TestCheckConfig_EngineDir(t *testing.T) {
+	e := newTestEngine(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	repoConfig := filepath.Join(repo, repoConfigName)
+	writeConfigFile(t, repoConfig, `{"commands": {"ck": {"args": [], "category": "状态操作"}}}`)
+	e.Dir = repo
+
+	var err error
+	output := captureOutput(e, func() { err = e.configCommand([]string{"jc"}) })
+	if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(output, "检查仓库配置: "+repoConfig) {
+		t.Errorf("应该检查 Engine.Dir 所在仓库的配置，得到 %v\n%s", err, output)
+	}
+}
+
+func TestIsConfigCheck(t *testing.T) {
+	if !isConfigCheck([]string{"-n", "pz", "jc", "a.json"}) {
+		t.Error("应该识别全局选项之后的 pz jc")
+	}
+	if isConfigCheck([]string{"pz"}) || isConfigCheck([]string{"zt"}) {
+		t.Error("不应该把其他命令识别为配置检查")
+	}
+}